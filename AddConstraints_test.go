@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"testing"
 
+	ic "github.com/marcuswu/dlineate/internal/constraint"
 	"github.com/marcuswu/dlineate/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,7 +17,7 @@ func TestAddAngleConstraint(t *testing.T) {
 	xDir := NewVector(0, -1, 0)
 	yDir := NewVector(1, 0, 0)
 	wp := NewWorkPlane(o, xDir, yDir)
-	s.SetWorkplane(wp)
+	s.UseWorkplane(wp)
 	p1 := s.AddPoint(0, 0)
 	l1 := s.AddLine(1, 0, 0, 1)
 	c1, err := s.AddAngleConstraint(p1, l1, 45, false)
@@ -233,6 +234,26 @@ func TestHorizontalConstraint(t *testing.T) {
 	assert.NotNil(t, c1, "Horizontal constraint on a line is valid")
 	assert.Nil(t, err, "Horizontal constraint on a line is valid")
 	assert.Equal(t, Resolved, c1.state, "Horizontal constraint should be resolved")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(0), &c1.constraints[0].Value), "Left to right line is at 0 degrees")
+
+	l2 := s.AddLine(2, 3, 0, 1)
+	c2, err := s.AddHorizontalConstraint(l2)
+	assert.Nil(t, err, "Horizontal constraint on a line is valid")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(math.Pi), &c2.constraints[0].Value), "Right to left line is at 180 degrees")
+
+	// A reversed line keeps its direction when solved
+	s = NewSketch()
+	l3 := s.AddLine(2.1, 0.2, 0, 0)
+	s.AddCoincidentConstraint(l3.End(), s.Origin)
+	s.AddHorizontalConstraint(l3)
+	s.AddDistanceConstraint(l3, nil, 2)
+	err = s.Solve()
+	assert.Nil(t, err, "Reversed horizontal line solves")
+	assert.InDelta(t, 2.0, l3.values[0], utils.StandardCompare, "Start stays right of end")
+	assert.InDelta(t, 0.0, l3.values[1], utils.StandardCompare, "Line is horizontal")
+	dir := l3.element.AsLine().Direction()
+	dirX, _ := dir.X.Float64()
+	assert.Less(t, dirX, 0.0, "Solved line points from start to end")
 }
 
 func TestVerticalConstraint(t *testing.T) {
@@ -247,6 +268,12 @@ func TestVerticalConstraint(t *testing.T) {
 	assert.NotNil(t, c1, "Vertical constraint on a line is valid")
 	assert.Nil(t, err, "Vertical constraint on a line is valid")
 	assert.Equal(t, Resolved, c1.state, "Vertical constraint should be resolved")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(0), &c1.constraints[0].Value), "Bottom to top line is at 0 degrees")
+
+	l2 := s.AddLine(2, 3, 0, 1)
+	c2, err := s.AddVerticalConstraint(l2)
+	assert.Nil(t, err, "Vertical constraint on a line is valid")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(math.Pi), &c2.constraints[0].Value), "Top to bottom line is at 180 degrees")
 }
 
 func TestAddPerpendicularConstraint(t *testing.T) {
@@ -262,6 +289,12 @@ func TestAddPerpendicularConstraint(t *testing.T) {
 	assert.NotNil(t, c1, "Perpendicular constraint between line and line is valid")
 	assert.Nil(t, err, "Perpendicular constraint between line and line is valid")
 	assert.Equal(t, Resolved, c1.state, "Perpendicular constraint should be resolved")
+
+	l3 := s.AddLine(0, 0, 1, 0)
+	l4 := s.AddLine(1, 0, 1, -1)
+	c2, err := s.AddPerpendicularConstraint(l3, l4)
+	assert.Nil(t, err, "Perpendicular constraint between line and line is valid")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(-math.Pi/2), &c2.constraints[0].Value), "Clockwise turn is -90 degrees")
 }
 
 func TestTangentConstraint(t *testing.T) {
@@ -287,3 +320,1104 @@ func TestTangentConstraint(t *testing.T) {
 	assert.Nil(t, err, "Tangent constraint between arc and line is valid")
 	assert.Equal(t, Resolved, c1.state, "Tangent constraint should be resolved")
 }
+
+func TestAddHorizontalDistanceConstraint(t *testing.T) {
+	s := NewSketch()
+	p1 := s.AddPoint(1, 1)
+	p2 := s.AddPoint(3, 2)
+	l1 := s.AddLine(0, 0, 1, 1)
+	l2 := s.AddLine(0, 1, 1, 2)
+	c1 := s.AddCircle(0, 0, 1)
+
+	c, err := s.AddHorizontalDistanceConstraint(l1, l2, 1)
+	assert.Nil(t, c, "Horizontal distance between lines is invalid")
+	assert.NotNil(t, err, "Horizontal distance between lines is invalid")
+	c, err = s.AddHorizontalDistanceConstraint(p1, c1, 1)
+	assert.Nil(t, c, "Horizontal distance to a circle is invalid")
+	assert.NotNil(t, err, "Horizontal distance to a circle is invalid")
+	c, err = s.AddHorizontalDistanceConstraint(p1, s.XAxis, 1)
+	assert.Nil(t, c, "Horizontal distance to the X axis is invalid")
+	assert.NotNil(t, err, "Horizontal distance to the X axis is invalid")
+
+	c, err = s.AddHorizontalDistanceConstraint(p1, p2, 2)
+	assert.Nil(t, err, "Horizontal distance between points is valid")
+	assert.Equal(t, HorizontalDistance, c.constraintType, "Constraint is a horizontal distance constraint")
+	assert.Equal(t, Unresolved, c.state, "Horizontal distance between unsolved points is unresolved")
+
+	c, err = s.AddHorizontalDistanceConstraint(s.Origin, p2, 2)
+	assert.Nil(t, err, "Horizontal distance from the origin is valid")
+	assert.Equal(t, Resolved, c.state, "Horizontal distance from the origin is resolved")
+	assert.Equal(t, 1, len(c.constraints), "Horizontal distance from the origin is resolved")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(2), &c.constraints[0].Value), "Distance from the Y axis")
+	assert.True(t, c.constraints[0].HasElementID(s.YAxis.element.GetID()), "Distance is from the Y axis")
+
+	c, err = s.AddHorizontalDistanceConstraint(p1, s.YAxis, -3)
+	assert.Nil(t, err, "Horizontal distance to the Y axis is valid")
+	assert.Equal(t, Resolved, c.state, "Horizontal distance to an axis is resolved")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(3), &c.constraints[0].Value), "Distance from the Y axis")
+
+	c, err = s.AddHorizontalDistanceConstraint(l1, p1, 1)
+	assert.Nil(t, err, "Horizontal distance to a line is valid")
+	assert.Equal(t, Unresolved, c.state, "Horizontal distance to an unsolved line is unresolved")
+}
+
+func TestAddVerticalDistanceConstraint(t *testing.T) {
+	s := NewSketch()
+	p1 := s.AddPoint(1, 1)
+	l1 := s.AddLine(0, 0, 1, 1)
+
+	c, err := s.AddVerticalDistanceConstraint(p1, s.YAxis, 1)
+	assert.Nil(t, c, "Vertical distance to the Y axis is invalid")
+	assert.NotNil(t, err, "Vertical distance to the Y axis is invalid")
+
+	c, err = s.AddVerticalDistanceConstraint(p1, s.Origin, 2)
+	assert.Nil(t, err, "Vertical distance from the origin is valid")
+	assert.Equal(t, VerticalDistance, c.constraintType, "Constraint is a vertical distance constraint")
+	assert.Equal(t, Resolved, c.state, "Vertical distance from the origin is resolved")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(2), &c.constraints[0].Value), "Distance from the X axis")
+	assert.True(t, c.constraints[0].HasElementID(s.XAxis.element.GetID()), "Distance is from the X axis")
+
+	c, err = s.AddVerticalDistanceConstraint(l1, p1, 1)
+	assert.Nil(t, err, "Vertical distance to a line is valid")
+	assert.Equal(t, Unresolved, c.state, "Vertical distance to an unsolved line is unresolved")
+}
+
+func TestAddConcentricConstraint(t *testing.T) {
+	s := NewSketch()
+	c1 := s.AddCircle(0, 0, 1)
+	a1 := s.AddArc(0.1, 0.2, 1.1, 0.2, 0.1, 1.2)
+	l1 := s.AddLine(0, 0, 1, 1)
+
+	c, err := s.AddConcentricConstraint(c1, l1)
+	assert.Nil(t, c, "Concentric constraint with a line is invalid")
+	assert.NotNil(t, err, "Concentric constraint with a line is invalid")
+	c, err = s.AddConcentricConstraint(c1, c1)
+	assert.Nil(t, c, "Concentric constraint with a single curve is invalid")
+	assert.NotNil(t, err, "Concentric constraint with a single curve is invalid")
+
+	c, err = s.AddConcentricConstraint(c1, a1)
+	assert.Nil(t, err, "Concentric constraint between a circle and an arc is valid")
+	assert.Equal(t, Concentric, c.constraintType, "Constraint is a concentric constraint")
+	assert.Equal(t, Unresolved, c.state, "Concentric constraint is unresolved until a center is placed")
+	assert.NotEqual(t, c1.Center().element.GetID(), a1.Center().element.GetID(), "Centers are not merged")
+	assert.Contains(t, s.eToC[c1.id], c, "Constraint is recorded for the circle")
+	assert.Contains(t, s.eToC[a1.id], c, "Constraint is recorded for the arc")
+
+	s = NewSketch()
+	c1 = s.AddCircle(0, 0, 1)
+	c2 := s.AddCircle(0.2, 0.1, 2)
+	_, err = s.AddConcentricConstraint(c1, c2)
+	assert.Nil(t, err, "Concentric constraint between circles is valid")
+	s.AddCoincidentConstraint(c1.Center(), s.Origin)
+	err = s.Solve()
+	assert.Nil(t, err, "Concentric circles solve")
+	assert.InDelta(t, 0, c2.Values()[0], utils.StandardCompare, "Circle centers are at the same location")
+	assert.InDelta(t, 0, c2.Values()[1], utils.StandardCompare, "Circle centers are at the same location")
+	assert.Equal(t, 0, len(s.ConflictingConstraints()), "No conflicting constraints")
+
+	// Centers on the far side of the axes from each other
+	s = NewSketch()
+	c1 = s.AddCircle(-2.1, -1.1, 1)
+	c2 = s.AddCircle(3, 2, 2)
+	c, _ = s.AddConcentricConstraint(c2, c1)
+	s.AddDistanceConstraint(c1.Center(), s.YAxis, 2)
+	s.AddDistanceConstraint(c1.Center(), s.XAxis, 1)
+	s.AddDistanceConstraint(c1, nil, 1)
+	s.AddDistanceConstraint(c2, nil, 2)
+	err = s.Solve()
+	assert.Nil(t, err, "Concentric circles solve")
+	assert.Equal(t, Solved, c.state, "Concentric constraint is solved")
+	assert.Equal(t, 2, len(c.constraints), "Concentric constraint has its own internal constraints")
+	assert.InDeltaSlice(t, []float64{-2, -1, 2}, c2.Values(), utils.StandardCompare, "Circle centers are at the same location")
+
+	// Removing the constraint leaves the centers independent
+	s.deleteConstraint(c)
+	s.AddDistanceConstraint(c2.Center(), s.YAxis, 3)
+	s.AddDistanceConstraint(c2.Center(), s.XAxis, 3)
+	err = s.Solve()
+	assert.Nil(t, err, "Circles solve without the concentric constraint")
+	assert.InDeltaSlice(t, []float64{-2, -1, 1}, c1.Values(), utils.StandardCompare, "Circle stays in place")
+	assert.InDeltaSlice(t, []float64{-3, -3, 2}, c2.Values(), utils.StandardCompare, "Circle centers are independent")
+
+	// Conflicts are reported against the concentric constraint
+	s = NewSketch()
+	c1 = s.AddCircle(-2.1, -1.1, 1)
+	c2 = s.AddCircle(3, 2, 2)
+	c, _ = s.AddConcentricConstraint(c2, c1)
+	s.AddDistanceConstraint(c1.Center(), s.YAxis, 2)
+	s.AddDistanceConstraint(c1.Center(), s.XAxis, 1)
+	s.AddDistanceConstraint(c1, nil, 1)
+	s.AddDistanceConstraint(c2, nil, 2)
+	s.AddDistanceConstraint(c2.Center(), s.YAxis, 3)
+	s.AddDistanceConstraint(c2.Center(), s.XAxis, 3)
+	err = s.Solve()
+	assert.NotNil(t, err, "Concentric circles with separately placed centers conflict")
+	assert.Contains(t, s.ConflictingConstraints(), c, "Concentric constraint is reported as conflicting")
+
+	// Fixed centers aren't moved onto each other
+	s = NewSketch()
+	c1 = s.AddCircle(1, 1, 1)
+	c2 = s.AddCircle(2, 2, 1)
+	s.MakeFixed(c1)
+	s.MakeFixed(c2)
+	c, err = s.AddConcentricConstraint(c1, c2)
+	assert.Nil(t, c, "Fixed centers at different locations can't be concentric")
+	assert.NotNil(t, err, "Fixed centers at different locations can't be concentric")
+
+	// The other center has a single solution rather than mirrors across the axes
+	s = NewSketch()
+	c1 = s.AddCircle(1, 1, 1)
+	c2 = s.AddCircle(-1.2, -0.9, 0.5)
+	s.AddDistanceConstraint(c1.Center(), s.YAxis, 1)
+	s.AddDistanceConstraint(c1.Center(), s.XAxis, 1)
+	s.AddDistanceConstraint(c1, nil, 1)
+	s.AddDistanceConstraint(c2, nil, 0.5)
+	c, _ = s.AddConcentricConstraint(c2, c1)
+	err = s.Solve()
+	assert.Nil(t, err, "Concentric circles solve")
+	assert.Equal(t, Solved, c.state, "Concentric constraint is solved")
+	dist, _ := c.constraints[0].Value.Float64()
+	assert.InDelta(t, 0, dist, utils.StandardCompare, "Center is no distance from the placed center")
+	assert.True(t, c.constraints[0].HasElements(c1.Center().element.GetID(), c2.Center().element.GetID()), "Center is constrained to the placed center")
+	assert.InDeltaSlice(t, []float64{1, 1, 0.5}, c2.Values(), utils.StandardCompare, "Circle is on the placed center rather than a mirror of it")
+}
+
+func TestCurveTangentConstraint(t *testing.T) {
+	s := NewSketch()
+	c1 := s.AddCircle(0, 0, 2)
+	c2 := s.AddCircle(3.2, 0.1, 1)
+	a1 := s.AddArc(13, 21, 34, 55, 89, 144)
+
+	c, err := s.AddCurveTangentConstraint(c1, c1, ExternalTangent)
+	assert.Nil(t, c, "Tangent constraint between a curve and itself should error")
+	assert.NotNil(t, err, "Tangent constraint between a curve and itself should error")
+
+	c, err = s.AddTangentConstraint(c1, a1)
+	assert.Nil(t, err, "Tangent constraint between circle and arc is valid")
+	assert.Equal(t, ExternalTangent, c.tangentMode, "Curves are externally tangent by default")
+	assert.Equal(t, Unresolved, c.state, "Tangent constraint should be unresolved")
+
+	tests := []struct {
+		name string
+		mode TangentMode
+		x    float64
+	}{
+		{"External", ExternalTangent, 3},
+		{"Internal", InternalTangent, 1},
+	}
+	for _, tt := range tests {
+		s = NewSketch()
+		c1 = s.AddCircle(0, 0, 2)
+		c2 = s.AddCircle(tt.x+0.2, 0.1, 1)
+		s.AddCoincidentConstraint(c1.Center(), s.Origin)
+		s.AddDistanceConstraint(c1, nil, 2)
+		s.AddDistanceConstraint(c2, nil, 1)
+		s.AddCoincidentConstraint(c2.Center(), s.XAxis)
+		c, err = s.AddCurveTangentConstraint(c1, c2, tt.mode)
+		assert.Nil(t, err, "%s: Tangent constraint between circles is valid", tt.name)
+		assert.Equal(t, Resolved, c.state, "%s: Tangent constraint should be resolved", tt.name)
+
+		err = s.Solve()
+		assert.Nil(t, err, "%s: Expected successful solve", tt.name)
+		assert.InDelta(t, tt.x, c2.Values()[0], utils.StandardCompare, "%s: Circle center", tt.name)
+		assert.InDelta(t, 0, c2.Values()[1], utils.StandardCompare, "%s: Circle center", tt.name)
+	}
+}
+
+func TestAddArcAngleConstraint(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 1, 1)
+	a1 := s.AddArc(0, 0, 2, 0, 0, -2)
+
+	c, err := s.AddArcAngleConstraint(l1, 90)
+	assert.Nil(t, c, "Arc angle constraint on a line is invalid")
+	assert.NotNil(t, err, "Arc angle constraint on a line is invalid")
+	c, err = s.AddArcAngleConstraint(a1, 360)
+	assert.Nil(t, c, "Arc angle of a full circle is invalid")
+	assert.NotNil(t, err, "Arc angle of a full circle is invalid")
+	c, err = s.AddArcAngleConstraint(a1, 270)
+	assert.Nil(t, err, "Arc angle over 180 degrees is valid")
+	assert.NotNil(t, c, "Arc angle over 180 degrees is valid")
+	s.removeConstraint(c)
+	c, err = s.AddArcAngleConstraint(a1, 180)
+	assert.Nil(t, err, "Arc angle of a half circle is valid")
+	assert.NotNil(t, c, "Arc angle of a half circle is valid")
+	s.removeConstraint(c)
+
+	c, err = s.AddArcAngleConstraint(a1, 90)
+	assert.Nil(t, err, "Arc angle constraint on an arc is valid")
+	assert.Equal(t, ArcAngle, c.constraintType, "Constraint is an arc angle constraint")
+	assert.Equal(t, Unresolved, c.state, "Arc angle constraint is unresolved without a radius")
+
+	s.AddDistanceConstraint(a1, nil, 2)
+	s.resolveConstraints()
+	assert.Equal(t, Resolved, c.state, "Arc angle constraint is resolved with a radius")
+	chord, _ := c.constraints[0].Value.Float64()
+	assert.InDelta(t, 2*math.Sqrt2, chord, utils.StandardCompare, "Chord of a 90 degree arc")
+	assert.True(t, c.constraints[0].HasElements(a1.Start().element.GetID(), a1.End().element.GetID()), "Chord is between start and end")
+
+	length, ok := s.resolveArcLength(a1)
+	assert.True(t, ok, "Arc length is known from its angle and radius")
+	assert.InDelta(t, math.Pi, length, utils.StandardCompare, "Length of a 90 degree arc with radius 2")
+}
+
+func TestAddArcLengthConstraint(t *testing.T) {
+	s := NewSketch()
+	a1 := s.AddArc(0, 0, 2, 0, 0, -2)
+
+	c, err := s.AddArcLengthConstraint(a1, 0)
+	assert.Nil(t, c, "Arc length must be positive")
+	assert.NotNil(t, err, "Arc length must be positive")
+	c, err = s.AddArcLengthConstraint(a1, 20)
+	assert.Nil(t, err, "Arc length constraint on an arc is valid")
+	s.AddDistanceConstraint(a1, nil, 2)
+	s.resolveConstraints()
+	assert.Equal(t, Unresolved, c.state, "Arc longer than its circle can't be resolved")
+	assert.NotNil(t, c.err, "Arc longer than its circle records why it can't be resolved")
+
+	s = NewSketch()
+	a1 = s.AddArc(0, 0, 2, 0, 0, -2)
+	s.AddDistanceConstraint(a1, nil, 2)
+	c, err = s.AddArcLengthConstraint(a1, math.Pi*4)
+	assert.Nil(t, c, "Arc as long as its circle with a known radius is invalid")
+	assert.NotNil(t, err, "Arc as long as its circle with a known radius is invalid")
+	c, err = s.AddArcLengthConstraint(a1, math.Pi*3)
+	assert.Nil(t, err, "Arc longer than half its circle is valid")
+	assert.Equal(t, Resolved, c.state, "Arc longer than half its circle is resolved")
+	assert.Len(t, c.sides, 1, "Arc longer than half its circle keeps its center on one side of the chord")
+	assert.Equal(t, 1.0, c.sides[0].Value, "Center is left of the chord of an arc over half a circle")
+
+	s = NewSketch()
+	a1 = s.AddArc(0, 0, 2, 0, 0, -2)
+	s.AddDistanceConstraint(a1, nil, 2)
+	c, err = s.AddArcLengthConstraint(a1, math.Pi*2)
+	assert.Nil(t, err, "Arc length constraint on an arc is valid")
+	assert.Equal(t, ArcLength, c.constraintType, "Constraint is an arc length constraint")
+	assert.Equal(t, Resolved, c.state, "Arc length constraint is resolved with a radius")
+	chord, _ := c.constraints[0].Value.Float64()
+	assert.InDelta(t, 4, chord, utils.StandardCompare, "Chord of a half circle is its diameter")
+
+	// Ratio and equal constraints compare arc lengths
+	l1 := s.AddLine(0, 0, 1, 1)
+	a2 := s.AddArc(0, 0, 1, 0, 0, -1)
+	c1 := s.AddCircle(0, 0, 1)
+	assert.Nil(t, s.AddRatioConstraint(a1, c1, 2), "Arc length can't be compared to a circle's radius")
+	assert.Nil(t, s.AddEqualConstraint(c1, a1), "Arc length can't be equal to a circle's radius")
+	ratio := s.AddRatioConstraint(a1, l1, 0.5)
+	assert.NotNil(t, ratio, "Ratio between an arc and a line is valid")
+	assert.Equal(t, Resolved, ratio.state, "Line length is resolved from the arc length")
+	v, _ := ratio.constraints[0].Value.Float64()
+	assert.InDelta(t, math.Pi, v, utils.StandardCompare, "Line is half the arc length")
+
+	l2 := s.AddLine(0, 0, 1, 1)
+	s.AddDistanceConstraint(l2, nil, 3)
+	equal := s.AddEqualConstraint(l2, a2)
+	assert.NotNil(t, equal, "Equal length between a line and an arc is valid")
+	assert.Equal(t, Unresolved, equal.state, "Arc length is unresolved without the arc radius")
+	s.AddDistanceConstraint(a2, nil, 4)
+	s.resolveConstraints()
+	assert.Equal(t, Resolved, equal.state, "Arc length is resolved with the arc radius")
+	v, _ = equal.constraints[0].Value.Float64()
+	assert.InDelta(t, 8*math.Sin(3.0/8), v, utils.StandardCompare, "Arc length equals the line length")
+}
+
+func TestAddCollinearConstraint(t *testing.T) {
+	s := NewSketch()
+	p1 := s.AddPoint(0, 1)
+	l1 := s.AddLine(0, 0, 1, 1)
+	l2 := s.AddLine(3, 3, 2, 2)
+
+	c, err := s.AddCollinearConstraint(p1, l1)
+	assert.Nil(t, c, "Collinear constraint with a point is invalid")
+	assert.NotNil(t, err, "Collinear constraint with a point is invalid")
+	c, err = s.AddCollinearConstraint(s.XAxis, s.YAxis)
+	assert.Nil(t, c, "Collinear constraint between axes is invalid")
+	assert.NotNil(t, err, "Collinear constraint between axes is invalid")
+
+	c, err = s.AddCollinearConstraint(l1, l2)
+	assert.Nil(t, err, "Collinear constraint between lines is valid")
+	assert.Equal(t, Collinear, c.constraintType, "Constraint is a collinear constraint")
+	assert.Equal(t, Resolved, c.state, "Collinear constraint should be resolved")
+	assert.Equal(t, 2, len(c.constraints), "Collinear constraint is an angle and a distance")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(math.Pi), &c.constraints[0].Value), "Lines in opposite directions are 180 degrees apart")
+	assert.True(t, c.constraints[1].HasElements(l1.element.GetID(), l2.Start().element.GetID()), "Second line's start is on the first line")
+
+	c, err = s.AddCollinearConstraint(l1, s.XAxis)
+	assert.Nil(t, err, "Collinear constraint with an axis is valid")
+	assert.True(t, c.constraints[1].HasElements(s.XAxis.element.GetID(), l1.Start().element.GetID()), "Line's start is on the axis")
+}
+
+func TestAddPointAngleConstraint(t *testing.T) {
+	s := NewSketch()
+	p1 := s.AddPoint(2, 0)
+	p2 := s.AddPoint(1, 1)
+	l1 := s.AddLine(0, 0, 1, 1)
+
+	c, err := s.AddPointAngleConstraint(p1, l1, p2, 45)
+	assert.Nil(t, c, "Point angle constraint with a line is invalid")
+	assert.NotNil(t, err, "Point angle constraint with a line is invalid")
+	c, err = s.AddPointAngleConstraint(p1, p1, p2, 45)
+	assert.Nil(t, c, "Point angle constraint at one of its points is invalid")
+	assert.NotNil(t, err, "Point angle constraint at one of its points is invalid")
+
+	numElements := len(s.Elements)
+	c, err = s.AddPointAngleConstraint(p1, s.Origin, p2, 45)
+	assert.Nil(t, err, "Point angle constraint between points is valid")
+	assert.Equal(t, PointAngle, c.constraintType, "Constraint is a point angle constraint")
+	assert.Equal(t, Resolved, c.state, "Point angle constraint should be resolved")
+	assert.Equal(t, 1, len(c.constraints), "Point angle constraint is a single angle")
+	assert.Equal(t, numElements+6, len(s.Elements), "Two helper lines are added")
+	for _, e := range s.Elements[numElements:] {
+		assert.True(t, e.hidden, "Helper lines are hidden")
+	}
+	for _, e := range s.Elements[numElements:] {
+		for _, other := range s.eToC[e.id] {
+			assert.NotEqual(t, Angle, other.constraintType, "Helper line angle is not a separate constraint")
+		}
+	}
+	assert.Contains(t, s.eToC[s.Origin.id], c, "Constraint is recorded for the vertex")
+}
+
+func TestAddInequalityConstraints(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 3, 0)
+	l2 := s.AddLine(0, 0, 0, 3)
+	p1 := s.AddPoint(1, 1)
+	c1 := s.AddCircle(2, 2, 1)
+
+	c, err := s.AddMinDistanceConstraint(p1, nil, 2)
+	assert.Nil(t, c, "Distance limit of a point is invalid")
+	assert.NotNil(t, err, "Distance limit of a point is invalid")
+	c, err = s.AddMaxDistanceConstraint(p1, c1, -1)
+	assert.Nil(t, c, "Negative distance limit is invalid")
+	assert.NotNil(t, err, "Negative distance limit is invalid")
+	c, err = s.AddMinAngleConstraint(l1, p1, 30)
+	assert.Nil(t, c, "Angle limit with a point is invalid")
+	assert.NotNil(t, err, "Angle limit with a point is invalid")
+	c, err = s.AddMaxAngleConstraint(l1, l2, 190)
+	assert.Nil(t, c, "Angle limit over 180 degrees is invalid")
+	assert.NotNil(t, err, "Angle limit over 180 degrees is invalid")
+
+	c, err = s.AddMinDistanceConstraint(l1, nil, 2)
+	assert.Nil(t, err, "Minimum line length is valid")
+	assert.Equal(t, MinDistance, c.constraintType, "Constraint is a minimum distance constraint")
+	assert.Equal(t, Resolved, c.state, "Inequality constraints are resolved when added")
+	assert.Equal(t, 0, len(c.constraints), "Inequalities are not added to the constraint graph")
+	assert.Equal(t, ic.Minimum, c.inequality.Bound, "Inequality has a minimum bound")
+	assert.True(t, c.inequality.HasElements(l1.Start().element.GetID(), l1.End().element.GetID()), "Line length is measured between its endpoints")
+	assert.Contains(t, s.eToC[l1.id], c, "Constraint is recorded for the line")
+
+	c, err = s.AddMaxDistanceConstraint(p1, c1, 4)
+	assert.Nil(t, err, "Maximum distance to a circle is valid")
+	assert.Equal(t, MaxDistance, c.constraintType, "Constraint is a maximum distance constraint")
+	assert.True(t, c.inequality.HasElements(p1.element.GetID(), c1.Center().element.GetID()), "Circles are measured from their center")
+
+	c, err = s.AddMinAngleConstraint(l1, l2, 30)
+	assert.Nil(t, err, "Minimum angle between lines is valid")
+	assert.Equal(t, MinAngle, c.constraintType, "Constraint is a minimum angle constraint")
+	limit, _ := c.inequality.GetValue().Float64()
+	assert.InDelta(t, math.Pi/6, limit, utils.StandardCompare, "Angle limit is stored in radians")
+	c, err = s.AddMaxAngleConstraint(l1, s.XAxis, 45)
+	assert.Nil(t, err, "Maximum angle to an axis is valid")
+	assert.Equal(t, MaxAngle, c.constraintType, "Constraint is a maximum angle constraint")
+	assert.False(t, c.IsActive(), "Constraints are inactive before solving")
+}
+
+func TestAddFillet(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 4, 0)
+	l2 := s.AddLine(0, 4, 0, 0)
+	l3 := s.AddLine(4, 0, 6, 6)
+	c1 := s.AddCircle(2, 2, 1)
+
+	arc, err := s.AddFillet(l1, c1, 1)
+	assert.Nil(t, arc, "Fillet with a circle is invalid")
+	assert.NotNil(t, err, "Fillet with a circle is invalid")
+	arc, err = s.AddFillet(l1, l2, 1)
+	assert.Nil(t, arc, "Fillet of lines without a shared corner is invalid")
+	assert.NotNil(t, err, "Fillet of lines without a shared corner is invalid")
+
+	s.AddCoincidentConstraint(l1.Start(), l2.End())
+	corner := l1.Start()
+	arc, err = s.AddFillet(l1, l2, 0)
+	assert.Nil(t, arc, "Fillet radius must be positive")
+	assert.NotNil(t, err, "Fillet radius must be positive")
+	arc, err = s.AddFillet(l1, l2, 5)
+	assert.Nil(t, arc, "Fillet radius is too large for the lines")
+	assert.NotNil(t, err, "Fillet radius is too large for the lines")
+	s.AddCoincidentConstraint(l3.Start(), l1.End())
+	arc, err = s.AddFillet(l1, l3, 1)
+	assert.Nil(t, err, "Fillet of lines at an angle is valid")
+	assert.NotNil(t, arc, "Fillet of lines at an angle is valid")
+
+	arc, err = s.AddFillet(l1, l2, 1)
+	assert.Nil(t, err, "Fillet of lines sharing a corner is valid")
+	assert.Equal(t, Arc, arc.elementType, "Fillet is an arc")
+	assert.Equal(t, []float64{1, 1, 1, 0, 0, 1}, arc.Values(), "Fillet arc runs clockwise between the tangent points")
+	assert.Equal(t, []float64{1, 0}, l1.Start().Values(), "First line starts at the tangent point")
+	assert.Equal(t, []float64{0, 1}, l2.End().Values(), "Second line ends at the tangent point")
+	assert.Equal(t, arc.Start().element.GetID(), l1.Start().element.GetID(), "Arc start is coincident with the first line")
+	assert.Equal(t, arc.End().element.GetID(), l2.End().element.GetID(), "Arc end is coincident with the second line")
+	assert.NotEqual(t, corner.element.GetID(), l1.Start().element.GetID(), "Corner point is no longer the line's start")
+
+	tangents := 0
+	for _, c := range s.eToC[arc.id] {
+		if c.constraintType == Tangent {
+			tangents++
+		}
+	}
+	assert.Equal(t, 2, tangents, "Fillet arc is tangent to both lines")
+	radius, ok := s.resolveCurveRadius(arc)
+	assert.True(t, ok, "Fillet radius is constrained")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(1), radius), "Fillet radius is constrained")
+}
+
+func TestAddChamfer(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 4, 0)
+	l2 := s.AddLine(0, 4, 0, 0)
+	l3 := s.AddLine(4, 0, 8, 0)
+	p1 := s.AddPoint(1, 1)
+
+	chamfer, err := s.AddChamfer(l1, p1, 1, 1)
+	assert.Nil(t, chamfer, "Chamfer with a point is invalid")
+	assert.NotNil(t, err, "Chamfer with a point is invalid")
+	chamfer, err = s.AddChamfer(l1, l2, 1, 1)
+	assert.Nil(t, chamfer, "Chamfer of lines without a shared corner is invalid")
+	assert.NotNil(t, err, "Chamfer of lines without a shared corner is invalid")
+
+	s.AddCoincidentConstraint(l1.End(), l3.Start())
+	chamfer, err = s.AddChamfer(l1, l3, 1, 1)
+	assert.Nil(t, chamfer, "Chamfer of parallel lines is invalid")
+	assert.NotNil(t, err, "Chamfer of parallel lines is invalid")
+
+	s.AddCoincidentConstraint(l1.Start(), l2.End())
+	corner := l1.Start()
+	chamfer, err = s.AddChamfer(l1, l2, 1, 4)
+	assert.Nil(t, chamfer, "Chamfer distance must be less than the line length")
+	assert.NotNil(t, err, "Chamfer distance must be less than the line length")
+	chamfer, err = s.AddAngleChamfer(l1, l2, 1, 90)
+	assert.Nil(t, chamfer, "Chamfer angle must form a triangle with the corner")
+	assert.NotNil(t, err, "Chamfer angle must form a triangle with the corner")
+
+	chamfer, err = s.AddChamfer(l1, l2, 1, 2)
+	assert.Nil(t, err, "Chamfer of lines sharing a corner is valid")
+	assert.Equal(t, Line, chamfer.elementType, "Chamfer is a line")
+	assert.Equal(t, []float64{1, 0, 0, 2}, chamfer.Values(), "Chamfer runs between the setbacks")
+	assert.Equal(t, chamfer.Start().element.GetID(), l1.Start().element.GetID(), "Chamfer start is coincident with the first line")
+	assert.Equal(t, chamfer.End().element.GetID(), l2.End().element.GetID(), "Chamfer end is coincident with the second line")
+
+	setbacks := 0
+	for _, c := range s.eToC[corner.id] {
+		if c.constraintType == Distance && len(c.elements) == 2 && c.elements[0].elementType == Point && c.elements[1].elementType == Point {
+			setbacks++
+		}
+	}
+	assert.Equal(t, 2, setbacks, "Chamfer setbacks are dimensioned from the corner")
+}
+
+func TestAddReferenceConstraints(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 3, 4)
+	l2 := s.AddLine(0, 0, 0, 2)
+	p1 := s.AddPoint(3, 0)
+	c1 := s.AddCircle(0, 4, 1)
+
+	c, err := s.AddReferenceDistance(p1, nil)
+	assert.Nil(t, c, "Reference distance of a point is invalid")
+	assert.NotNil(t, err, "Reference distance of a point is invalid")
+	c, err = s.AddReferenceDistance(l1, l2)
+	assert.Nil(t, c, "Reference distance between lines is invalid")
+	assert.NotNil(t, err, "Reference distance between lines is invalid")
+	c, err = s.AddReferenceAngle(l1, p1)
+	assert.Nil(t, c, "Reference angle with a point is invalid")
+	assert.NotNil(t, err, "Reference angle with a point is invalid")
+
+	c, err = s.AddReferenceDistance(l1, nil)
+	assert.Nil(t, err, "Reference line length is valid")
+	assert.Equal(t, ReferenceDistance, c.constraintType, "Constraint is a reference distance")
+	assert.True(t, c.IsReference(), "Constraint is a reference dimension")
+	assert.Equal(t, 0, len(c.constraints), "Reference dimensions add no internal constraints")
+	assert.InDelta(t, 5, c.Value(), utils.StandardCompare, "Line length is measured when added")
+
+	c, _ = s.AddReferenceDistance(p1, l2)
+	assert.InDelta(t, 3, c.Value(), utils.StandardCompare, "Distance is measured perpendicular to the line")
+	c, _ = s.AddReferenceDistance(c1, s.XAxis)
+	assert.InDelta(t, 4, c.Value(), utils.StandardCompare, "Distance is measured from the circle's center")
+	c, _ = s.AddReferenceDistance(p1, c1)
+	assert.InDelta(t, 5, c.Value(), utils.StandardCompare, "Distance is measured from the circle's center")
+	c, _ = s.AddReferenceAngle(l1, l2)
+	assert.InDelta(t, 90-(math.Atan2(4, 3)*180/math.Pi), c.Value(), utils.StandardCompare, "Angle is counter-clockwise from the first line")
+	c, _ = s.AddReferenceAngle(l2, s.XAxis)
+	assert.InDelta(t, -90, c.Value(), utils.StandardCompare, "Angle is counter-clockwise from the first line")
+
+	c = s.AddDistanceConstraint(l1, nil, 5)
+	assert.InDelta(t, 5, c.Value(), utils.StandardCompare, "Distance constraints have a value")
+	c, _ = s.AddAngleConstraint(l1, l2, 30, false)
+	assert.InDelta(t, 30, c.Value(), utils.StandardCompare, "Angle constraints have a value in degrees")
+	c, _ = s.AddAngleConstraint(l1, l2, 30, true)
+	assert.InDelta(t, 150, c.Value(), utils.StandardCompare, "Supplementary angle constraints have the supplementary value")
+
+	l3 := s.AddLine(0, 1, 3, 1)
+	l4 := s.AddLine(3, 2, 0, 2)
+	c, _ = s.AddParallelConstraint(l3, s.XAxis)
+	assert.InDelta(t, 0, c.Value(), utils.StandardCompare, "Parallel lines in the same direction hold 0 degrees")
+	c, _ = s.AddParallelConstraint(l3, l4)
+	assert.InDelta(t, 180, c.Value(), utils.StandardCompare, "Parallel lines in opposite directions hold 180 degrees")
+	c, _ = s.AddPerpendicularConstraint(l3, l2)
+	assert.InDelta(t, 90, c.Value(), utils.StandardCompare, "Perpendicular constraints hold 90 degrees counter-clockwise")
+	c, _ = s.AddPerpendicularConstraint(l2, l3)
+	assert.InDelta(t, -90, c.Value(), utils.StandardCompare, "Perpendicular constraints hold -90 degrees clockwise")
+	s.SetUnits(Millimeter, Radian)
+	assert.InDelta(t, -math.Pi/2, c.Value(), utils.StandardCompare, "Perpendicular values are in the sketch's angle unit")
+	c, _ = s.AddParallelConstraint(l3, l4)
+	assert.InDelta(t, math.Pi, c.Value(), utils.StandardCompare, "Parallel values are in the sketch's angle unit")
+	assert.Equal(t, 0.0, s.AddCoincidentConstraint(p1, l3).Value(), "Coincident constraints have no value")
+}
+
+func TestAddExpressionConstraints(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 3, 0)
+	l2 := s.AddLine(0, 0, 0, 2)
+	p1 := s.AddPoint(1, 1)
+
+	assert.NotNil(t, s.SetParameter("2height", 2), "Parameter names must be valid identifiers")
+	assert.NotNil(t, s.SetParameter("sqrt", 2), "Function names can't be parameters")
+	assert.NotNil(t, s.SetParameterExpression("width", "2 *"), "Parameter expressions must parse")
+	assert.Nil(t, s.SetParameterExpression("width", "2 * height + 1"), "Parameters can use parameters set later")
+	_, err := s.Parameter("width")
+	assert.NotNil(t, err, "Unknown parameters are an error when evaluated")
+	assert.Nil(t, s.SetParameter("height", 2))
+	v, err := s.Parameter("width")
+	assert.Nil(t, err)
+	assert.Equal(t, 5.0, v, "Parameter expressions are evaluated from other parameters")
+
+	err = s.SetParameterExpression("height", "width / 2")
+	assert.NotNil(t, err, "Parameter cycles are an error")
+	assert.Contains(t, err.Error(), "height -> width -> height", "Error describes the cycle")
+	v, _ = s.Parameter("height")
+	assert.Equal(t, 2.0, v, "Parameter is unchanged after a cycle error")
+	assert.NotNil(t, s.SetParameterExpression("depth", "depth + 1"), "Parameters can't refer to themselves")
+	_, err = s.Parameter("depth")
+	assert.NotNil(t, err, "Parameter with a cycle is not added")
+
+	c, err := s.AddDistanceExpression(l1, nil, "depth")
+	assert.Nil(t, c, "Expressions with unknown parameters are invalid")
+	assert.NotNil(t, err, "Expressions with unknown parameters are invalid")
+	c, err = s.AddAngleExpression(l1, p1, "45", false)
+	assert.Nil(t, c, "Angle expressions need lines")
+	assert.NotNil(t, err, "Angle expressions need lines")
+	c, err = s.AddRatioExpression(l1, p1, "2")
+	assert.Nil(t, c, "Ratio expressions need lines or curves")
+	assert.NotNil(t, err, "Ratio expressions need lines or curves")
+
+	c, err = s.AddDistanceExpression(l1, nil, "width")
+	assert.Nil(t, err, "Distance expression is valid")
+	assert.Equal(t, Distance, c.constraintType, "Constraint is a distance constraint")
+	assert.Equal(t, "width", c.Expression(), "Constraint keeps its expression")
+	assert.Equal(t, 5.0, c.Value(), "Constraint value is evaluated from the expression")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(5), &c.constraints[0].Value), "Internal constraint uses the evaluated value")
+	angle, err := s.AddAngleExpression(l1, l2, "height * 45", false)
+	assert.Nil(t, err, "Angle expression is valid")
+	assert.Equal(t, 90.0, angle.Value(), "Angle is evaluated in degrees")
+	ratio, err := s.AddRatioExpression(l1, l2, "1 / height")
+	assert.Nil(t, err, "Ratio expression is valid")
+	assert.Equal(t, 0.5, ratio.Value(), "Ratio is evaluated from the expression")
+	assert.Equal(t, "", s.AddDistanceConstraint(l2, nil, 2).Expression(), "Constraints without an expression have an empty expression")
+
+	// Changing a parameter before solving updates constraint values
+	assert.Nil(t, s.SetParameter("height", 1))
+	assert.Equal(t, 3.0, c.Value(), "Constraint value is updated from the expression")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(3), &c.constraints[0].Value), "Internal constraint is updated")
+	assert.Equal(t, 45.0, angle.Value(), "Angle value is updated from the expression")
+	assert.Equal(t, 0, utils.StandardBigFloatCompare(big.NewFloat(math.Pi/4), &angle.constraints[0].Value), "Internal angle is updated")
+	assert.Equal(t, 1.0, ratio.Value(), "Ratio value is updated from the expression")
+
+	// A parameter the constraints can't be evaluated with is put back
+	assert.NotNil(t, s.SetParameterExpression("height", "sqrt(0 - 4)"), "Constraint expressions must evaluate")
+	v, _ = s.Parameter("height")
+	assert.Equal(t, 1.0, v, "Parameter is unchanged after an evaluation error")
+	assert.Equal(t, 3.0, c.Value(), "Constraint value is unchanged after an evaluation error")
+	assert.NotNil(t, s.SetParameterExpression("width", "depth * 2"), "Constraint expressions must evaluate")
+	v, _ = s.Parameter("width")
+	assert.Equal(t, 3.0, v, "Parameter expression is unchanged after an evaluation error")
+
+	// Trigonometric functions take the sketch's angle unit
+	assert.Nil(t, s.SetParameterExpression("rise", "2 * sin(30)"))
+	v, _ = s.Parameter("rise")
+	assert.InDelta(t, 1, v, utils.StandardCompare, "Trigonometric functions take degrees by default")
+	s.SetUnits(Millimeter, Radian)
+	v, _ = s.Parameter("rise")
+	assert.InDelta(t, 2*math.Sin(30), v, utils.StandardCompare, "Trigonometric functions take the sketch's angle unit")
+	assert.Nil(t, s.SetParameter("third", math.Pi/3))
+	c, err = s.AddDistanceExpression(l2, nil, "4 * cos(third)")
+	assert.Nil(t, err, "Distance expression with a trigonometric function is valid")
+	assert.InDelta(t, 2, c.Value(), utils.StandardCompare, "Constraint expressions take the sketch's angle unit")
+}
+
+func TestAddAreaConstraints(t *testing.T) {
+	s := NewSketch()
+	lines := addClosedLoop(s, 0, 0, 4, 0, 4, 3, 0, 3)
+	open := s.AddLine(5, 0, 6, 0)
+	c1 := s.AddCircle(8, 8, 1)
+
+	c, err := s.AddAreaConstraint([]*Element{lines[0], lines[1], lines[2]}, 10)
+	assert.Nil(t, c, "Area of an open loop is invalid")
+	assert.NotNil(t, err, "Area of an open loop is invalid")
+	c, err = s.AddAreaConstraint(append(lines, open), 10)
+	assert.Nil(t, c, "Area with an unconnected line is invalid")
+	assert.NotNil(t, err, "Area with an unconnected line is invalid")
+	c, err = s.AddPerimeterConstraint([]*Element{c1}, 10)
+	assert.Nil(t, c, "Perimeter of a circle is invalid")
+	assert.NotNil(t, err, "Perimeter of a circle is invalid")
+	c, err = s.AddAreaConstraint(lines, 0)
+	assert.Nil(t, c, "Zero area is invalid")
+	assert.NotNil(t, err, "Zero area is invalid")
+
+	// Lines may be given in any order
+	c, err = s.AddAreaConstraint([]*Element{lines[2], lines[0], lines[3], lines[1]}, 10)
+	assert.Nil(t, err, "Area of a closed loop is valid")
+	assert.Equal(t, Area, c.constraintType, "Constraint is an area constraint")
+	assert.Equal(t, Resolved, c.state, "Area constraints are resolved when added")
+	assert.Equal(t, 0, len(c.constraints), "Area constraints are not added to the constraint graph")
+	assert.Equal(t, 4, len(c.loop.Points), "Area is measured through each corner")
+	assert.Contains(t, s.eToC[lines[1].id], c, "Constraint is recorded for the lines")
+	assert.Equal(t, 10.0, c.Value(), "Area value")
+
+	s.SetUnits(Centimeter, Degree)
+	assert.InDelta(t, 0.1, c.Value(), utils.StandardCompare, "Area is in the sketch unit squared")
+	c, err = s.AddPerimeterConstraint(lines, 2)
+	assert.Nil(t, err, "Perimeter of a closed loop is valid")
+	assert.Equal(t, Perimeter, c.constraintType, "Constraint is a perimeter constraint")
+	assert.InDelta(t, 20, c.loop.Value, utils.StandardCompare, "Perimeter is stored in millimeters")
+	assert.InDelta(t, 2, c.Value(), utils.StandardCompare, "Perimeter is in the sketch unit")
+}
+
+func TestSplitTrimExtend(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 10, 0)
+	l2 := s.AddLine(8, -2, 8, 2)
+	c1 := s.AddCircle(20, 0, 3)
+	a1 := s.AddArc(0, 0, 0, 5, 5, 0)
+
+	piece, err := s.Split(c1, 20, 3)
+	assert.Nil(t, piece, "Split of a circle is invalid")
+	assert.NotNil(t, err, "Split of a circle is invalid")
+	piece, err = s.Split(l1, 10, 1)
+	assert.Nil(t, piece, "Split at an end is invalid")
+	assert.NotNil(t, err, "Split at an end is invalid")
+	p, err := s.Trim(l2, c1, 8, 0)
+	assert.Nil(t, p, "Trim needs the elements to cross")
+	assert.NotNil(t, err, "Trim needs the elements to cross")
+	p, err = s.Trim(l1, l1, 9, 0)
+	assert.Nil(t, p, "Trim at itself is invalid")
+	assert.NotNil(t, err, "Trim at itself is invalid")
+	p, err = s.Extend(a1, l2)
+	assert.Nil(t, p, "Extend of an arc is invalid")
+	assert.NotNil(t, err, "Extend of an arc is invalid")
+	p, err = s.Extend(l1, l2)
+	assert.Nil(t, p, "Extend needs to meet the element beyond the line's ends")
+	assert.NotNil(t, err, "Extend needs to meet the element beyond the line's ends")
+
+	// Trimming removes the part picked past the crossing
+	originalEnd := l1.End()
+	p, err = s.Trim(l1, l2, 9, 0.5)
+	assert.Nil(t, err, "Trim of crossing lines is valid")
+	assert.Equal(t, []float64{0, 0, 8, 0}, l1.Values(), "Line is trimmed at the crossing")
+	assert.Equal(t, p, l1.End(), "Trimmed end is the line's new end")
+	assert.Equal(t, Coincident, s.eToC[p.id][len(s.eToC[p.id])-1].constraintType, "Trimmed end is coincident with the cutting line")
+	assert.True(t, originalEnd.hidden, "Unconstrained end point is removed")
+	assert.Equal(t, 0, len(s.eToC[originalEnd.id]), "Unconstrained end point is removed")
+
+	// Splitting keeps the start and moves the rest to a new line
+	end := l1.End()
+	piece, err = s.Split(l1, 3, 1)
+	assert.Nil(t, err, "Split of a line is valid")
+	assert.Equal(t, Line, piece.elementType, "Split piece is a line")
+	assert.Equal(t, []float64{0, 0, 3, 0}, l1.Values(), "Line ends at the split point")
+	assert.Equal(t, []float64{3, 0, 8, 0}, piece.Values(), "Split piece runs to the original end")
+	assert.Equal(t, l1.End().element.GetID(), piece.Start().element.GetID(), "Pieces are joined at the split point")
+	assert.Equal(t, end.element.GetID(), piece.End().element.GetID(), "Split piece ends at the original end point")
+
+	// Arcs are split on their circle and keep their center
+	piece, err = s.Split(a1, 4, 4)
+	assert.Nil(t, err, "Split of an arc is valid")
+	assert.Equal(t, Arc, piece.elementType, "Split piece is an arc")
+	assert.InDeltaSlice(t, []float64{0, 0, 0, 5, 5 / math.Sqrt2, 5 / math.Sqrt2}, a1.Values(), utils.StandardCompare, "Arc ends at the split point")
+	assert.InDeltaSlice(t, []float64{0, 0, 5 / math.Sqrt2, 5 / math.Sqrt2, 5, 0}, piece.Values(), utils.StandardCompare, "Split piece runs to the original end")
+	assert.Equal(t, a1.Center().element.GetID(), piece.Center().element.GetID(), "Split arcs share a center")
+	assert.Equal(t, 0, len(s.eToC[piece.id]), "Split arc is held on the circle by the original arc")
+
+	// Trimming an arc by a circle
+	c2 := s.AddCircle(5, 0, 3)
+	p, err = s.Trim(piece, c2, 3.8, 3.2)
+	assert.Nil(t, err, "Trim of an arc by a circle is valid")
+	assert.InDelta(t, 4.1, p.Values()[0], utils.StandardCompare, "Arc is trimmed where the circles cross")
+	assert.InDelta(t, math.Sqrt(25-(4.1*4.1)), p.Values()[1], utils.StandardCompare, "Arc is trimmed where the circles cross")
+	assert.Equal(t, p, piece.Start(), "Arc end nearer the crossing is trimmed")
+
+	// Either side of a line crossing an element twice can be trimmed, but not between the crossings
+	l5 := s.AddLine(0, -10, 10, -10)
+	c5 := s.AddCircle(5, -10, 2)
+	p, err = s.Trim(l5, c5, 5, -10)
+	assert.Nil(t, p, "Trim between crossings is invalid")
+	assert.NotNil(t, err, "Trim between crossings is invalid")
+	p, err = s.Trim(l5, c5, 9, -11)
+	assert.Nil(t, err, "Trim of the end of a line is valid")
+	assert.Equal(t, []float64{0, -10, 7, -10}, l5.Values(), "Line end is trimmed at the crossing nearer the pick")
+	assert.Equal(t, p, l5.End(), "Trimmed end is the line's new end")
+	p, err = s.Trim(l5, c5, 1, -9)
+	assert.Nil(t, err, "Trim of the start of a line is valid")
+	assert.Equal(t, []float64{3, -10, 7, -10}, l5.Values(), "Line start is trimmed at the crossing nearer the pick")
+	assert.Equal(t, p, l5.Start(), "Trimmed end is the line's new start")
+
+	// Extending moves the nearer end to the element
+	l3 := s.AddLine(1, 4, 3, 4)
+	p, err = s.Extend(l3, s.YAxis)
+	assert.Nil(t, err, "Extend to an axis is valid")
+	assert.Equal(t, []float64{0, 4, 3, 4}, l3.Values(), "Line start is extended to the axis")
+	assert.Equal(t, p, l3.Start(), "Extended end is the line's new start")
+	c3 := s.AddCircle(8, 4, 2)
+	p, err = s.Extend(l3, c3)
+	assert.Nil(t, err, "Extend to a circle is valid")
+	assert.Equal(t, []float64{0, 4, 6, 4}, l3.Values(), "Line end is extended to the nearer side of the circle")
+	assert.Equal(t, p, l3.End(), "Extended end is the line's new end")
+
+	// Constraints on the element itself follow its new end points
+	l4 := s.AddLine(0, 8, 10, 8)
+	length := s.AddDistanceConstraint(l4, nil, 10)
+	limit, _ := s.AddMaxDistanceConstraint(l4, nil, 12)
+	originalEnd = l4.End()
+	piece, err = s.Split(l4, 6, 8)
+	assert.Nil(t, err, "Split of a dimensioned line is valid")
+	assert.Equal(t, 1, len(length.constraints), "Length keeps one internal constraint")
+	assert.True(t, length.constraints[0].HasElementID(l4.End().element.GetID()), "Length is measured to the split point")
+	assert.False(t, length.constraints[0].HasElementID(originalEnd.element.GetID()), "Length no longer spans both pieces")
+	assert.True(t, limit.inequality.HasElementID(l4.End().element.GetID()), "Length limit is measured to the split point")
+	a2 := s.AddArc(0, 20, 0, 25, 5, 20)
+	arcAngle, _ := s.AddArcAngleConstraint(a2, 90)
+	s.AddDistanceConstraint(a2, nil, 5)
+	s.resolveConstraint(arcAngle)
+	assert.Equal(t, Resolved, arcAngle.state, "Arc angle is resolved once the radius is known")
+	_, err = s.Split(a2, 5/math.Sqrt2, 20+(5/math.Sqrt2))
+	assert.Nil(t, err, "Split of a dimensioned arc is valid")
+	assert.Equal(t, Unresolved, arcAngle.state, "Arc angle is resolved again for the first piece")
+}
+
+func TestAddIntersectionPoint(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 10, 0)
+	l2 := s.AddLine(2, -1, 2, 1)
+	l3 := s.AddLine(0, 1, 10, 1)
+	c1 := s.AddCircle(5, 0, 3)
+	a1 := s.AddArc(9, 0, 9, 3, 12, 0)
+	p1 := s.AddPoint(1, 1)
+
+	p, err := s.AddIntersectionPoint(l1, p1, 0)
+	assert.Nil(t, p, "Points are not intersected")
+	assert.NotNil(t, err, "Points are not intersected")
+	p, err = s.AddIntersectionPoint(l1, l1, 0)
+	assert.Nil(t, p, "An element does not intersect itself")
+	assert.NotNil(t, err, "An element does not intersect itself")
+	p, err = s.AddIntersectionPoint(l1, l3, 0)
+	assert.Nil(t, p, "Parallel lines do not intersect")
+	assert.NotNil(t, err, "Parallel lines do not intersect")
+	p, err = s.AddIntersectionPoint(l1, l2, 1)
+	assert.Nil(t, p, "Lines intersect once")
+	assert.NotNil(t, err, "Lines intersect once")
+	p, err = s.AddIntersectionPoint(c1, s.AddCircle(5, 0, 1), 0)
+	assert.Nil(t, p, "Concentric circles do not intersect")
+	assert.NotNil(t, err, "Concentric circles do not intersect")
+	p, err = s.AddIntersectionPoint(c1, s.AddLine(0, 5, 10, 5), 0)
+	assert.Nil(t, p, "Elements must meet to intersect")
+	assert.NotNil(t, err, "Elements must meet to intersect")
+
+	// Lines meet beyond their ends
+	p, err = s.AddIntersectionPoint(l2, s.XAxis, 0)
+	assert.Nil(t, err, "Line and axis intersect")
+	assert.InDeltaSlice(t, []float64{2, 0}, p.Values(), utils.StandardCompare, "Line and axis intersect")
+	p, err = s.AddIntersectionPoint(l3, s.AddLine(4, 2, 5, 3), 0)
+	assert.Nil(t, err, "Lines intersect beyond their ends")
+	assert.InDeltaSlice(t, []float64{3, 1}, p.Values(), utils.StandardCompare, "Lines intersect beyond their ends")
+	assert.Equal(t, Point, p.elementType, "Intersection is a point")
+	assert.Equal(t, 2, len(s.eToC[p.id]), "Intersection point is coincident with both lines")
+	for _, c := range s.eToC[p.id] {
+		assert.Equal(t, Coincident, c.constraintType, "Intersection point is coincident with both lines")
+	}
+
+	// Branches along a line through a circle
+	p, err = s.AddIntersectionPoint(c1, l1, 0)
+	assert.Nil(t, err, "Circle and line intersect")
+	assert.InDeltaSlice(t, []float64{2, 0}, p.Values(), utils.StandardCompare, "Branch 0 is nearer the line's start")
+	p, err = s.AddIntersectionPoint(l1, c1, 1)
+	assert.Nil(t, err, "Line and circle intersect")
+	assert.InDeltaSlice(t, []float64{8, 0}, p.Values(), utils.StandardCompare, "Branch 1 is farther from the line's start")
+
+	// Branches either side of the line between circle centers, using the arc's full circle
+	p, err = s.AddIntersectionPoint(c1, a1, 0)
+	assert.Nil(t, err, "Circle and arc intersect")
+	assert.InDeltaSlice(t, []float64{7, math.Sqrt(5)}, p.Values(), utils.StandardCompare, "Branch 0 is to the left")
+	p, err = s.AddIntersectionPoint(c1, a1, 1)
+	assert.Nil(t, err, "Circle and arc intersect")
+	assert.InDeltaSlice(t, []float64{7, -math.Sqrt(5)}, p.Values(), utils.StandardCompare, "Branch 1 is to the right")
+	p, err = s.AddIntersectionPoint(a1, c1, 0)
+	assert.Nil(t, err, "Arc and circle intersect")
+	assert.InDeltaSlice(t, []float64{7, -math.Sqrt(5)}, p.Values(), utils.StandardCompare, "Branches follow the order of the elements")
+}
+
+func TestPatterns(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 2, 0)
+	l2 := s.AddLine(2, 0, 2, 1)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	c1 := s.AddCircle(5, 0, 1)
+	a1 := s.AddArc(0, 0, 0, 1, 1, 0)
+
+	copies, err := s.CircularPattern([]*Element{a1}, 2, s.Origin, 90)
+	assert.Nil(t, copies, "Points at the center must be the center")
+	assert.NotNil(t, err, "Points at the center must be the center")
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddCoincidentConstraint(s.Origin, a1.Center())
+	copies, err = s.LinearPattern(nil, 3, s.XAxis, 1)
+	assert.Nil(t, copies, "Pattern requires elements")
+	assert.NotNil(t, err, "Pattern requires elements")
+	copies, err = s.LinearPattern([]*Element{l1}, 1, s.XAxis, 1)
+	assert.Nil(t, copies, "Pattern requires copies")
+	assert.NotNil(t, err, "Pattern requires copies")
+	copies, err = s.LinearPattern([]*Element{s.YAxis}, 2, s.XAxis, 1)
+	assert.Nil(t, copies, "Axes are not patterned")
+	assert.NotNil(t, err, "Axes are not patterned")
+	copies, err = s.LinearPattern([]*Element{l1}, 2, c1, 1)
+	assert.Nil(t, copies, "Linear pattern direction is a line or axis")
+	assert.NotNil(t, err, "Linear pattern direction is a line or axis")
+	copies, err = s.LinearPattern([]*Element{l1}, 2, s.XAxis, 0)
+	assert.Nil(t, copies, "Linear pattern spacing is positive")
+	assert.NotNil(t, err, "Linear pattern spacing is positive")
+	copies, err = s.CircularPattern([]*Element{l1}, 2, l2, 90)
+	assert.Nil(t, copies, "Circular pattern center is a point")
+	assert.NotNil(t, err, "Circular pattern center is a point")
+	copies, err = s.CircularPattern([]*Element{l1}, 2, s.Origin, 360)
+	assert.Nil(t, copies, "Circular pattern angle rotates the elements")
+	assert.NotNil(t, err, "Circular pattern angle rotates the elements")
+	copies, err = s.LinearPatternExpression([]*Element{l1}, 2, s.XAxis, "missing")
+	assert.Nil(t, copies, "Pattern expressions use defined parameters")
+	assert.NotNil(t, err, "Pattern expressions use defined parameters")
+
+	copies, err = s.LinearPattern([]*Element{l1, l2, c1}, 3, s.YAxis, 4)
+	assert.Nil(t, err, "Linear pattern is valid")
+	assert.Equal(t, 2, len(copies), "Pattern returns each instance after the seed")
+	for i, instance := range copies {
+		offset := 4 * float64(i+1)
+		assert.Equal(t, 3, len(instance), "Each instance copies every element")
+		assert.Equal(t, []float64{0, offset, 2, offset}, instance[0].Values(), "Lines are copied along the direction")
+		assert.Equal(t, []float64{2, offset, 2, 1 + offset}, instance[1].Values(), "Lines are copied along the direction")
+		assert.Equal(t, []float64{5, offset, 1}, instance[2].Values(), "Circles are copied along the direction")
+		assert.Equal(t, instance[0].End().element.GetID(), instance[1].Start().element.GetID(), "Copies share the points their seeds share")
+		assert.Equal(t, Ratio, s.eToC[instance[2].id][len(s.eToC[instance[2].id])-1].constraintType, "Circle copies are equal to the seed")
+	}
+
+	copies, err = s.CircularPatternExpression([]*Element{a1, l1}, 4, s.Origin, "90")
+	assert.Nil(t, err, "Circular pattern is valid")
+	assert.Equal(t, 3, len(copies), "Pattern returns each instance after the seed")
+	assert.Equal(t, Arc, copies[0][0].elementType, "Arcs are copied")
+	assert.InDeltaSlice(t, []float64{0, 0, -1, 0, 0, 1}, copies[0][0].Values(), utils.StandardCompare, "Arcs are rotated about the center")
+	assert.InDeltaSlice(t, []float64{0, 0, -2, 0}, copies[1][1].Values(), utils.StandardCompare, "Lines are rotated about the center")
+	for _, instance := range copies {
+		assert.Equal(t, s.Origin.element.GetID(), instance[0].Center().element.GetID(), "Points at the center are not moved")
+		assert.Equal(t, s.Origin.element.GetID(), instance[1].Start().element.GetID(), "Points at the center are not moved")
+	}
+	for _, c := range s.eToC[copies[2][0].Start().id] {
+		if c.constraintType == Angle {
+			assert.Equal(t, "90", c.Expression(), "Rotations use the pattern expression")
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 2, 0)
+	l2 := s.AddLine(2, 0, 1, 1)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	c1 := s.AddCircle(3, 2, 1)
+	a1 := s.AddArc(2, 2, 2, 3, 3, 2)
+	p1 := s.AddPoint(4, 1)
+
+	for _, tt := range []struct {
+		name     string
+		elements []*Element
+		axis     *Element
+	}{
+		{"Mirror requires elements", nil, s.YAxis},
+		{"Mirror axis is a line or axis", []*Element{l1}, c1},
+		{"Mirror axis is a line or axis", []*Element{l1}, nil},
+		{"Axes are not mirrored", []*Element{l1, s.XAxis}, s.YAxis},
+		{"The mirror line is not mirrored", []*Element{l1, l2}, l2},
+	} {
+		copies, err := s.Mirror(tt.elements, tt.axis)
+		assert.Nil(t, copies, tt.name)
+		assert.NotNil(t, err, tt.name)
+	}
+
+	c, err := s.AddSymmetricConstraint(l1, p1, s.YAxis)
+	assert.Nil(t, c, "Symmetric constraints are between points")
+	assert.NotNil(t, err, "Symmetric constraints are between points")
+	c, err = s.AddSymmetricConstraint(p1, l1.Start(), c1)
+	assert.Nil(t, c, "Symmetric constraints are across a line or axis")
+	assert.NotNil(t, err, "Symmetric constraints are across a line or axis")
+	c, err = s.AddSymmetricConstraint(p1, p1, s.YAxis)
+	assert.Nil(t, c, "Symmetric points are distinct")
+	assert.NotNil(t, err, "Symmetric points are distinct")
+
+	copies, err := s.Mirror([]*Element{l1, l2, c1, a1, p1}, s.YAxis)
+	assert.Nil(t, err, "Expected mirror to be added")
+	assert.Equal(t, 5, len(copies), "Mirror returns a copy of each element")
+	assert.Equal(t, []float64{0, 0, -2, 0}, copies[0].Values(), "Lines are reflected")
+	assert.Equal(t, []float64{-2, 0, -1, 1}, copies[1].Values(), "Lines are reflected")
+	assert.Equal(t, []float64{-3, 2, 1}, copies[2].Values(), "Circles are reflected")
+	assert.Equal(t, []float64{-2, 2, -3, 2, -2, 3}, copies[3].Values(), "Arcs are reflected and reversed")
+	assert.Equal(t, []float64{-4, 1}, copies[4].Values(), "Points are reflected")
+	assert.Equal(t, copies[0].End().element.GetID(), copies[1].Start().element.GetID(), "Copies share the points their originals share")
+	assert.Equal(t, l1.Start().element.GetID(), copies[0].Start().element.GetID(), "Points on the axis are shared")
+	assert.Equal(t, Ratio, s.eToC[copies[2].id][len(s.eToC[copies[2].id])-1].constraintType, "Circle copies are equal to the original")
+	symmetric := 0
+	for _, c := range s.constraints {
+		if c.constraintType == Symmetric {
+			symmetric++
+		}
+	}
+	assert.Equal(t, 7, symmetric, "Each copied point is symmetric with its original")
+}
+
+func TestShapes(t *testing.T) {
+	s := NewSketch()
+	sides, err := s.AddRectangle(0, 0, 0, 2)
+	assert.Nil(t, sides, "Rectangles have a width and height")
+	assert.NotNil(t, err, "Rectangles have a width and height")
+	sides, err = s.AddRectangle(0, 0, 3, 2)
+	assert.Nil(t, err, "Expected rectangle to be added")
+	assert.Equal(t, 4, len(sides), "Rectangles have four sides")
+	assert.Equal(t, []float64{0, 0, 3, 0}, sides[0].Values(), "Rectangle sides start at the first corner")
+	assert.Equal(t, []float64{3, 2, 0, 2}, sides[2].Values(), "Rectangle sides run around the corners")
+	for i, side := range sides {
+		assert.Equal(t, side.End().element.GetID(), sides[(i+1)%4].Start().element.GetID(), "Rectangle sides are joined")
+	}
+
+	sides, err = s.AddCenterRectangle(2, 1, 3, 3)
+	assert.Nil(t, err, "Expected center rectangle to be added")
+	assert.Equal(t, 7, len(sides), "Center rectangles have four sides, two diagonals and a center")
+	assert.Equal(t, []float64{1, -1, 3, -1}, sides[0].Values(), "Center rectangles start opposite the corner")
+	assert.True(t, sides[4].IsConstruction(), "Diagonals are construction lines")
+	assert.Equal(t, []float64{2, 1}, sides[6].Values(), "Center rectangles have a center point")
+
+	sides, err = s.AddRegularPolygon(0, 0, 1, 2)
+	assert.Nil(t, sides, "Polygons have at least three sides")
+	assert.NotNil(t, err, "Polygons have at least three sides")
+	sides, err = s.AddRegularPolygon(0, 0, 0, 4)
+	assert.Nil(t, sides, "Polygons have a radius")
+	assert.NotNil(t, err, "Polygons have a radius")
+	sides, err = s.AddRegularPolygon(0, 0, math.Sqrt2, 4)
+	assert.Nil(t, err, "Expected polygon to be added")
+	assert.Equal(t, 5, len(sides), "Polygons return their sides and center")
+	assert.InDeltaSlice(t, []float64{-1, -1, 1, -1}, sides[0].Values(), utils.StandardCompare, "The first side is at the bottom")
+	assert.InDeltaSlice(t, []float64{1, 1, -1, 1}, sides[2].Values(), utils.StandardCompare, "Sides run counter-clockwise")
+
+	c1 := s.AddPoint(0, 0)
+	c2 := s.AddPoint(4, 0)
+	sides, err = s.AddSlot(c1, c1, 1)
+	assert.Nil(t, sides, "Slots are between two points")
+	assert.NotNil(t, err, "Slots are between two points")
+	sides, err = s.AddSlot(c1, c2, 0)
+	assert.Nil(t, sides, "Slots have a radius")
+	assert.NotNil(t, err, "Slots have a radius")
+	sides, err = s.AddSlot(c1, c2, 1)
+	assert.Nil(t, err, "Expected slot to be added")
+	assert.Equal(t, 4, len(sides), "Slots have two lines and two arcs")
+	assert.InDeltaSlice(t, []float64{0, -1, 4, -1}, sides[0].Values(), utils.StandardCompare, "Slot lines are offset from the centers")
+	assert.InDeltaSlice(t, []float64{4, 0, 4, 1, 4, -1}, sides[1].Values(), utils.StandardCompare, "Slot arcs run around the centers")
+	assert.Equal(t, c2.element.GetID(), sides[1].Center().element.GetID(), "Slot arcs are centered on the points")
+	radius, err := s.findConstraint(Distance, sides[3])
+	assert.Nil(t, err, "Slot radius is dimensioned")
+	assert.Equal(t, 1.0, radius.dataValue, "Slot radius is dimensioned")
+
+	p1 := s.AddPoint(1, 0)
+	p2 := s.AddPoint(0, 1)
+	p3 := s.AddPoint(-1, 0)
+	arc, err := s.AddArcThroughPoints(p1, p2, p1)
+	assert.Nil(t, arc, "Points on an arc are not in a line")
+	assert.NotNil(t, err, "Points on an arc are not in a line")
+	arc, err = s.AddArcThroughPoints(p1, c1, p3)
+	assert.Nil(t, arc, "Points on an arc are not in a line")
+	assert.NotNil(t, err, "Points on an arc are not in a line")
+	arc, err = s.AddArcThroughPoints(p1, p2, sides[0])
+	assert.Nil(t, arc, "Arcs are through points")
+	assert.NotNil(t, err, "Arcs are through points")
+	arc, err = s.AddArcThroughPoints(p1, p2, p3)
+	assert.Nil(t, err, "Expected arc to be added")
+	assert.InDeltaSlice(t, []float64{0, 0, -1, 0, 1, 0}, arc.Values(), utils.StandardCompare, "Arcs run clockwise through the points")
+	assert.Equal(t, p3.element.GetID(), arc.Start().element.GetID(), "Arcs share their ends with the points")
+	circle, err := s.AddCircleThroughPoints(p1, p2, p3)
+	assert.Nil(t, err, "Expected circle to be added")
+	assert.InDeltaSlice(t, []float64{0, 0, 1}, circle.Values(), utils.StandardCompare, "Circles pass through the points")
+	through := s.eToC[circle.id][0]
+	assert.Equal(t, ThroughPoints, through.constraintType, "Circle is held by a through points constraint")
+	assert.Equal(t, []*Element{circle, p1, p2, p3}, through.elements, "Constraint is between the circle and its points")
+	assert.Equal(t, Unresolved, through.state, "Constraint is resolved once the points are placed")
+}
+
+func TestBlocks(t *testing.T) {
+	src := NewSketch()
+	l1 := src.AddLine(0, 0, 2, 0)
+	a1 := src.AddArc(2, 1, 2, 0, 2, 2)
+	src.AddCoincidentConstraint(l1.End(), a1.Start())
+	c1 := src.AddCircle(1, 1, 0.5)
+
+	b, err := src.NewBlock(nil, l1, src.XAxis)
+	assert.Nil(t, b, "Block base is a point")
+	assert.NotNil(t, err, "Block base is a point")
+	b, err = src.NewBlock(nil, src.Origin, c1)
+	assert.Nil(t, b, "Block axis is a line or axis")
+	assert.NotNil(t, err, "Block axis is a line or axis")
+	b, err = src.NewBlock([]*Element{l1, src.XAxis}, src.Origin, src.XAxis)
+	assert.Nil(t, b, "Axes are not block elements")
+	assert.NotNil(t, err, "Axes are not block elements")
+	b, err = NewSketch().NewBlock(nil, src.Origin, src.XAxis)
+	assert.Nil(t, b, "Blocks require elements")
+	assert.NotNil(t, err, "Blocks require elements")
+	b, err = src.NewBlock(nil, src.Origin, src.XAxis)
+	assert.Nil(t, err, "Expected block to be created")
+	assert.Equal(t, []*Element{l1, a1, c1}, b.Elements(), "Blocks default to the sketch's elements")
+
+	s := NewSketch()
+	i, err := s.InsertBlock(nil, 0, 0, 0, false)
+	assert.Nil(t, i, "Inserting requires a block")
+	assert.NotNil(t, err, "Inserting requires a block")
+	i, err = s.InsertBlock(b, 1, 1, 90, false)
+	assert.Nil(t, err, "Expected block to be inserted")
+	assert.Equal(t, b, i.Block(), "Instances are copies of their block")
+	assert.False(t, i.IsParametric(), "Instance is rigid")
+	assert.Equal(t, 3, len(i.Elements()), "Instances copy each block element")
+	assert.Equal(t, []float64{1, 1}, i.Base().Values(), "Instance base is placed")
+	assert.True(t, i.Axis().IsConstruction(), "Instance axis is a construction line")
+	assert.InDeltaSlice(t, []float64{1, 1, 1, 3}, i.Elements()[0].Values(), utils.StandardCompare, "Lines are moved and rotated")
+	assert.InDeltaSlice(t, []float64{0, 3, 1, 3, -1, 3}, i.Elements()[1].Values(), utils.StandardCompare, "Arcs are moved and rotated")
+	assert.InDeltaSlice(t, []float64{0, 2, 0.5}, i.Elements()[2].Values(), utils.StandardCompare, "Circles are moved and rotated")
+	assert.Equal(t, i.Base().element.GetID(), i.Elements()[0].Start().element.GetID(), "Points at the block base are the instance base")
+	assert.Equal(t, i.Elements()[0].End().element.GetID(), i.Elements()[1].Start().element.GetID(), "Copies share the points their originals share")
+	assert.NotNil(t, s.UpdateBlock(i), "Rigid instances are not updated")
+
+	i, err = s.InsertBlock(b, 0, 0, 0, true)
+	assert.Nil(t, err, "Expected block to be inserted")
+	assert.True(t, i.IsParametric(), "Instance is parametric")
+	assert.Nil(t, s.UpdateBlock(i), "Parametric instances are updated")
+
+	// Blocks are checked before any of their elements are copied
+	b, err = src.NewBlock(nil, l1.Start(), l1)
+	assert.Nil(t, err, "Expected block to be created")
+	l1.values[2], l1.values[3] = 0, 0
+	elements, constraints := len(s.Elements), len(s.constraints)
+	i, err = s.InsertBlock(b, 0, 0, 0, false)
+	assert.Nil(t, i, "Block axis must have a length")
+	assert.NotNil(t, err, "Block axis must have a length")
+	assert.Equal(t, elements, len(s.Elements), "Failed insert adds no elements")
+	assert.Equal(t, constraints, len(s.constraints), "Failed insert adds no constraints")
+}
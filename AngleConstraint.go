@@ -19,15 +19,38 @@ func AngleConstraint(p1 *Element, p2 *Element) *Constraint {
 	return constraint
 }
 
-// AddAngleConstraint adds a constraint between the lines p1 and p2 where the counter-clockwise angle in degrees
+// AddAngleConstraint adds a constraint between the lines p1 and p2 where the counter-clockwise angle
 // from p1 to p2 is the positive direction
 func (s *Sketch) AddAngleConstraint(p1 *Element, p2 *Element, v float64, useSupplementary bool) (*Constraint, error) {
+	return s.addAngle(p1, p2, s.units.toAngle(v), useSupplementary)
+}
+
+// addAngle adds an angle constraint with the angle v in degrees
+func (s *Sketch) addAngle(p1 *Element, p2 *Element, v float64, useSupplementary bool) (*Constraint, error) {
 	c := AngleConstraint(p1, p2)
 
 	if (p1.elementType != Line && p1.elementType != Axis) || (p2.elementType != Line && p2.elementType != Axis) {
 		return nil, errors.New("incorrect element types for angle constraint")
 	}
 
+	radians := angleRadians(v, useSupplementary)
+	c.supplementary = useSupplementary
+	c.dataValue, _ = radians.Float64()
+	c.dataValue *= 180 / math.Pi
+
+	constraint := s.sketch.AddConstraint(ic.Angle, p1.element, p2.element, radians)
+	p1.constraints = append(p1.constraints, constraint)
+	p2.constraints = append(p2.constraints, constraint)
+	c.constraints = append(c.constraints, constraint)
+	s.constraints = append(s.constraints, c)
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+
+	return c, nil
+}
+
+// angleRadians converts an angle constraint value in degrees to radians, using the supplementary angle if requested
+func angleRadians(v float64, useSupplementary bool) *big.Float {
 	var halfCir, pi, angle, radians, radiansAlt, t big.Float
 	halfCir.SetPrec(utils.FloatPrecision).SetFloat64(180)
 	pi.SetPrec(utils.FloatPrecision).SetFloat64(math.Pi)
@@ -44,14 +67,5 @@ func (s *Sketch) AddAngleConstraint(p1 *Element, p2 *Element, v float64, useSupp
 		// if useSupplementary || math.Abs(math.Abs(currentAngle)-math.Abs(radiansAlt)) < math.Abs(math.Abs(currentAngle)-math.Abs(radians)) {
 		radians.Set(&radiansAlt)
 	}
-
-	constraint := s.sketch.AddConstraint(ic.Angle, p1.element, p2.element, &radians)
-	p1.constraints = append(p1.constraints, constraint)
-	p2.constraints = append(p2.constraints, constraint)
-	c.constraints = append(c.constraints, constraint)
-	s.constraints = append(s.constraints, c)
-	s.eToC[p1.id] = append(s.eToC[p1.id], c)
-	s.eToC[p2.id] = append(s.eToC[p2.id], c)
-
-	return c, nil
+	return &radians
 }
@@ -0,0 +1,259 @@
+package dlineate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	ic "github.com/marcuswu/dlineate/internal/constraint"
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func ArcAngleConstraint(p1 *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	constraint.constraintType = ArcAngle
+	constraint.state = Unresolved
+
+	return constraint
+}
+
+func ArcLengthConstraint(p1 *Element) *Constraint {
+	c := ArcAngleConstraint(p1)
+	c.constraintType = ArcLength
+	return c
+}
+
+// AddArcAngleConstraint adds a constraint setting the included angle of the arc p1.
+// The angle is measured clockwise from the arc's start to its end and must be greater than 0 and less than
+// 360 degrees. The constraint is resolved into a distance between the arc's start and end once its radius
+// is known, along with the side of that chord the center is on, which tells an angle from its complement.
+func (s *Sketch) AddArcAngleConstraint(p1 *Element, v float64) (*Constraint, error) {
+	if p1.elementType != Arc {
+		return nil, errors.New("incorrect element types for arc angle constraint")
+	}
+	v = s.units.toAngle(v)
+	if v <= 0 || utils.StandardFloatCompare(v, 360) >= 0 {
+		return nil, errors.New("arc angle must be greater than 0 and less than 360 degrees")
+	}
+
+	c := ArcAngleConstraint(p1)
+	c.dataValue = v
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.constraints = append(s.constraints, c)
+
+	s.resolveArcConstraint(c)
+
+	return c, nil
+}
+
+// AddArcLengthConstraint adds a constraint setting the length along the arc p1 from its start to its end.
+// The constraint is resolved like an arc angle once its radius is known, so the length must be less than
+// the circle's circumference. It returns an error if the radius is already known and the arc is too long.
+// Otherwise a length found to be too long once the radius is known is reported by Solve.
+func (s *Sketch) AddArcLengthConstraint(p1 *Element, v float64) (*Constraint, error) {
+	if p1.elementType != Arc {
+		return nil, errors.New("incorrect element types for arc length constraint")
+	}
+	v = s.units.toLength(v)
+	if v <= 0 {
+		return nil, errors.New("arc length must be greater than 0")
+	}
+	if radius, ok := s.resolveCurveRadius(p1); ok {
+		if err := arcSweepError(radius, v); err != nil {
+			return nil, err
+		}
+	}
+
+	c := ArcLengthConstraint(p1)
+	c.dataValue = v
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.constraints = append(s.constraints, c)
+
+	s.resolveArcConstraint(c)
+
+	return c, nil
+}
+
+// arcSweep returns the included angle in radians of an arc from its current values.
+// Arcs run clockwise from start to end, so the result is in the range (0, 2pi].
+func (e *Element) arcSweep() (float64, bool) {
+	if e.elementType != Arc {
+		return 0, false
+	}
+	sx := e.values[2] - e.values[0]
+	sy := e.values[3] - e.values[1]
+	ex := e.values[4] - e.values[0]
+	ey := e.values[5] - e.values[1]
+	// Counter-clockwise angle from start to end, negated for clockwise
+	sweep := -math.Atan2((sx*ey)-(sy*ex), (sx*ex)+(sy*ey))
+	if sweep <= 0 {
+		sweep += 2 * math.Pi
+	}
+	return sweep, true
+}
+
+func (s *Sketch) resolveArcConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+
+	arc := c.elements[0]
+	radius, ok := s.resolveCurveRadius(arc)
+	if !ok {
+		return false
+	}
+	r, _ := radius.Float64()
+
+	sweep := c.dataValue * math.Pi / 180
+	if c.constraintType == ArcLength {
+		sweep = c.dataValue / r
+	}
+
+	constraint := s.addArcSweepConstraint(c, arc, r, sweep)
+	if constraint == nil {
+		return false
+	}
+	utils.Logger.Debug().
+		Uint("constraint", constraint.GetID()).
+		Str("type", c.constraintType.String()).
+		Msg("resolveArcConstraint: added constraint")
+	c.constraints = append(c.constraints, constraint)
+	c.state = Resolved
+
+	return c.state == Resolved
+}
+
+// arcSweepError returns an error if an arc of the radius can't be the length
+func arcSweepError(radius *big.Float, length float64) error {
+	r, _ := radius.Float64()
+	if utils.StandardFloatCompare(length, 2*math.Pi*r) >= 0 {
+		return fmt.Errorf("arc length %f must be less than the circumference %f of its circle", length, 2*math.Pi*r)
+	}
+	return nil
+}
+
+// addArcSweepConstraint constrains the chord from an arc's start to its end for a sweep angle in radians.
+// The chord is the same for a sweep and its complement, so the center is kept on the side of the chord the
+// sweep puts it on: the right of start to end under half a circle and the left over half a circle. The side
+// is the orientation of the center, start and end, which reflects the end across the line from the center
+// through the start when it isn't met, moving it to the other end of the chord on the same circle.
+// It returns nil and records the error on c if the sweep angle is not in (0, 2pi).
+func (s *Sketch) addArcSweepConstraint(c *Constraint, arc *Element, radius float64, sweep float64) *ic.Constraint {
+	if sweep <= 0 || utils.StandardFloatCompare(sweep, 2*math.Pi) >= 0 {
+		utils.Logger.Error().
+			Float64("sweep", sweep).
+			Uint("arc", arc.id).
+			Msg("Arc sweep angle must be greater than 0 and less than 2pi")
+		c.err = fmt.Errorf("arc %d with radius %f can't sweep %f radians, which must be less than 2pi", arc.id, radius, sweep)
+		return nil
+	}
+	c.err = nil
+
+	chord := 2 * radius * math.Sin(sweep/2)
+	constraint := s.addDistanceConstraint(arc.Start(), arc.End(), chord)
+	if constraint != nil {
+		arc.Start().constraints = append(arc.Start().constraints, constraint)
+		arc.End().constraints = append(arc.End().constraints, constraint)
+	}
+	if side := float64(utils.StandardFloatCompare(sweep, math.Pi)); side != 0 {
+		points := []el.SketchElement{s.currentPoint(arc.Center()), s.currentPoint(arc.Start()), s.currentPoint(arc.End())}
+		s.addDerivedSide(c, numeric.LoopOrientation, points, side)
+	}
+	return constraint
+}
+
+// resolveArcLength returns the length of an arc if it is known
+func (s *Sketch) resolveArcLength(e *Element) (float64, bool) {
+	if e.elementType != Arc {
+		return 0, false
+	}
+
+	for _, c := range s.eToC[e.id] {
+		if c.constraintType == ArcLength {
+			return c.dataValue, true
+		}
+	}
+
+	radius, ok := s.resolveCurveRadius(e)
+	if !ok {
+		return 0, false
+	}
+	r, _ := radius.Float64()
+	for _, c := range s.eToC[e.id] {
+		if c.constraintType == ArcAngle {
+			return r * c.dataValue * math.Pi / 180, true
+		}
+	}
+
+	solved := s.isElementSolved(e.children[0]) && s.isElementSolved(e.children[1]) && s.isElementSolved(e.children[2])
+	if !solved {
+		return 0, false
+	}
+	arc := *e
+	arc.values = make([]float64, len(e.values))
+	for i, child := range e.children {
+		current, ok := s.sketch.GetElement(child.element.GetID())
+		if !ok {
+			current = child.element
+		}
+		arc.values[i*2], _ = current.AsPoint().GetX().Float64()
+		arc.values[i*2+1], _ = current.AsPoint().GetY().Float64()
+	}
+	sweep, _ := arc.arcSweep()
+	return r * sweep, true
+}
+
+// resolveArcLengthRatio resolves a ratio constraint where p2's length is p1's length * constraint value
+// and at least one of the elements is an arc
+func (s *Sketch) resolveArcLengthRatio(c *Constraint) bool {
+	p1 := c.elements[0]
+	p2 := c.elements[1]
+
+	if length, ok := s.resolveElementLength(p1); ok && s.constrainLength(c, p2, length*c.dataValue) {
+		c.state = Resolved
+		return true
+	}
+	if length, ok := s.resolveElementLength(p2); ok && s.constrainLength(c, p1, length/c.dataValue) {
+		c.state = Resolved
+		return true
+	}
+
+	return c.state == Resolved
+}
+
+// resolveElementLength returns the length of a line or arc if it is known
+func (s *Sketch) resolveElementLength(e *Element) (float64, bool) {
+	if e.elementType == Arc {
+		return s.resolveArcLength(e)
+	}
+	return s.resolveLineLength(e)
+}
+
+// constrainLength adds internal constraints to c setting the length of a line or arc
+func (s *Sketch) constrainLength(c *Constraint, e *Element, length float64) bool {
+	var constraint *ic.Constraint
+	switch e.elementType {
+	case Line:
+		constraint = s.addDistanceConstraint(e, nil, length)
+	case Arc:
+		radius, ok := s.resolveCurveRadius(e)
+		if !ok {
+			return false
+		}
+		r, _ := radius.Float64()
+		constraint = s.addArcSweepConstraint(c, e, r, length/r)
+	}
+	if constraint == nil {
+		return false
+	}
+	utils.Logger.Debug().
+		Uint("constraint", constraint.GetID()).
+		Msg("resolveRatioConstraint: added constraint")
+	e.constraints = append(e.constraints, constraint)
+	c.constraints = append(c.constraints, constraint)
+	return true
+}
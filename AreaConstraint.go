@@ -0,0 +1,155 @@
+package dlineate
+
+import (
+	"errors"
+
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func LoopConstraint(loop []*Element, ctype ConstraintType) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, loop...)
+	constraint.constraintType = ctype
+	constraint.state = Resolved
+
+	return constraint
+}
+
+// AddAreaConstraint adds a constraint on the area enclosed by a loop of lines in the sketch's length unit squared.
+// The lines may be given in any order and direction but must join end to end in a single closed loop.
+// Area constraints are enforced by the numeric solver after the other constraints are solved, so they can
+// drive a dimension the other constraints leave free.
+func (s *Sketch) AddAreaConstraint(loop []*Element, area float64) (*Constraint, error) {
+	return s.addLoopConstraint(loop, s.units.toCanonical(Area, area), Area)
+}
+
+// AddPerimeterConstraint adds a constraint on the total length of a loop of lines.
+// The lines must join end to end in a single closed loop, as with AddAreaConstraint.
+// Perimeter constraints are enforced by the numeric solver after the other constraints are solved.
+func (s *Sketch) AddPerimeterConstraint(loop []*Element, perimeter float64) (*Constraint, error) {
+	return s.addLoopConstraint(loop, s.units.toCanonical(Perimeter, perimeter), Perimeter)
+}
+
+// loopPoints returns the internal points at the start of each edge of a closed loop of lines
+func loopPoints(loop []*Element) ([]el.SketchElement, error) {
+	for _, e := range loop {
+		if e.elementType != Line {
+			return nil, errors.New("area and perimeter constraints require a loop of lines")
+		}
+	}
+	loops, err := findLoops(loop)
+	if err != nil {
+		return nil, err
+	}
+	if len(loops) != 1 {
+		return nil, errors.New("elements must form a single closed loop")
+	}
+	points := make([]el.SketchElement, 0, len(loop))
+	for _, edge := range loops[0].Edges {
+		start := edge.Element.Start()
+		if edge.Reversed {
+			start = edge.Element.End()
+		}
+		points = append(points, start.element)
+	}
+	return points, nil
+}
+
+func (s *Sketch) addLoopConstraint(loop []*Element, v float64, ctype ConstraintType) (*Constraint, error) {
+	if v <= 0 {
+		return nil, errors.New("area and perimeter must be positive")
+	}
+	points, err := loopPoints(loop)
+	if err != nil {
+		return nil, err
+	}
+	c := LoopConstraint(loop, ctype)
+	c.dataValue = v
+
+	measure := numeric.LoopArea
+	if ctype == Perimeter {
+		measure = numeric.LoopPerimeter
+	}
+	c.loop = s.sketch.AddLoopConstraint(measure, points, v)
+	s.addInequality(c)
+
+	return c, nil
+}
+
+// addSideConstraint adds a constraint keeping the last of points on one side, 1 or -1, of a line given by
+// the others. Like area and perimeter constraints, sides are enforced by the numeric solver.
+func (s *Sketch) addSideConstraint(elements []*Element, measure numeric.LoopMeasure, points []el.SketchElement, side float64) *Constraint {
+	c := LoopConstraint(elements, Side)
+	c.dataValue = side
+	c.loop = s.sketch.AddLoopConstraint(measure, points, side)
+	s.addInequality(c)
+	return c
+}
+
+// addDerivedSide keeps a point a derived constraint places on one side with a side constraint which is
+// removed along with the constraint's internal constraints
+func (s *Sketch) addDerivedSide(c *Constraint, measure numeric.LoopMeasure, points []el.SketchElement, side float64) {
+	c.sides = append(c.sides, s.sketch.AddLoopConstraint(measure, points, side))
+}
+
+// removeDerivedSides removes the side constraints a derived constraint added when it was resolved
+func (s *Sketch) removeDerivedSides(c *Constraint) {
+	for _, side := range c.sides {
+		s.sketch.RemoveLoopConstraint(side)
+	}
+	c.sides = nil
+}
+
+// lineSide returns the measure and points of a side constraint keeping p on one side of a line or axis
+// along with the side [x, y] is on: 1, -1 or 0 on the line. Lines are measured by the orientation of their
+// end points and p. Axes are measured by the distance to p along the other axis.
+func (s *Sketch) lineSide(line *Element, p *Element, x float64, y float64) (numeric.LoopMeasure, []el.SketchElement, float64) {
+	measure := numeric.LoopOrientation
+	var points []el.SketchElement
+	if line.elementType == Line {
+		points = []el.SketchElement{s.currentPoint(line.children[0]), s.currentPoint(line.children[1]), s.currentPoint(p)}
+	} else {
+		other := s.XAxis
+		if line == s.XAxis {
+			other = s.YAxis
+		}
+		measure = numeric.LoopProjection
+		points = []el.SketchElement{other.element, s.currentPoint(s.Origin), s.currentPoint(p)}
+	}
+
+	at := append([]el.SketchElement{}, points...)
+	at[len(at)-1] = el.CopySketchElement(points[len(points)-1])
+	el.SetElementValues(at[len(at)-1], []float64{x, y})
+	value := numeric.NewLoopConstraint(measure, nil, 0).Current(at)
+	return measure, points, float64(utils.StandardFloatCompare(value, 0))
+}
+
+func (c *Constraint) isLoop() bool {
+	return c.loop != nil
+}
+
+// isFreedomInLoops returns whether the only freedom the graph leaves is what the area and perimeter
+// constraints remove, one degree each. Side constraints remove none. The freedom must be in the elements
+// connected to the loops' points -- no loop can remove freedom from elements it isn't connected to.
+func (s *Sketch) isFreedomInLoops() bool {
+	loops := 0
+	for _, c := range s.constraints {
+		if c.isLoop() && !c.loop.IsSide() {
+			loops++
+		}
+	}
+	inLoops, outside := s.sketch.LoopFreedom()
+	return loops > 0 && outside <= 0 && inLoops <= loops
+}
+
+// isEveryConstraintResolved returns whether every constraint has been resolved into internal constraints
+func (s *Sketch) isEveryConstraintResolved() bool {
+	for _, c := range s.constraints {
+		if c.state == Unresolved {
+			return false
+		}
+	}
+	return true
+}
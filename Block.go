@@ -0,0 +1,273 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// Block is a group of elements from a sketch which may be inserted into sketches as a unit, such as a
+// profile used several times. Its elements are placed relative to a reference point and axis.
+type Block struct {
+	elements []*Element
+	base     *Element
+	axis     *Element
+}
+
+// BlockInstance is a copy of a block's elements inserted into a sketch. A parametric instance isn't linked
+// to its block's elements and only takes up their changes when it is updated with UpdateBlock.
+type BlockInstance struct {
+	block      *Block
+	elements   []*Element
+	base       *Element
+	axis       *Element
+	parametric bool
+	// the constraints placing each copied point relative to the instance's base and axis
+	placements []blockPlacement
+	radii      []blockRadius
+	length     *Constraint
+}
+
+// blockPlacement places a copied point by its distances from the ends of the instance's axis and the side
+// of the axis it is on
+type blockPlacement struct {
+	source   *Element
+	distance *Constraint
+	reach    *Constraint
+	side     *Constraint
+}
+
+// blockRadius is the radius of a copied circle
+type blockRadius struct {
+	source *Element
+	radius *Constraint
+}
+
+// NewBlock creates a block from elements of the sketch, placed relative to the point base and the direction
+// of axis, which may be a line or axis. The base and axis may be elements of the block or any point and line
+// of the sketch, such as its origin and X axis. If elements is empty, the block is every visible point, line,
+// circle and arc of the sketch.
+func (s *Sketch) NewBlock(elements []*Element, base *Element, axis *Element) (*Block, error) {
+	if base == nil || base.elementType != Point {
+		return nil, errors.New("block base must be a point")
+	}
+	if axis == nil || (axis.elementType != Line && axis.elementType != Axis) {
+		return nil, errors.New("block axis must be a line or axis")
+	}
+	if len(elements) == 0 {
+		for _, e := range s.Elements {
+			if e.isChild || e.hidden || e == s.Origin {
+				continue
+			}
+			switch e.elementType {
+			case Point, Line, Circle, Arc:
+				elements = append(elements, e)
+			}
+		}
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("block requires elements")
+	}
+	for _, e := range elements {
+		if e == nil {
+			return nil, errors.New("incorrect element types for block")
+		}
+		switch e.elementType {
+		case Point, Line, Circle, Arc:
+		default:
+			return nil, errors.New("incorrect element types for block")
+		}
+	}
+	b := &Block{elements: append([]*Element{}, elements...), base: base, axis: axis}
+	if _, _, ok := b.frame(); !ok {
+		return nil, errors.New("block axis must have a length")
+	}
+	return b, nil
+}
+
+// Elements returns the elements of the block
+func (b *Block) Elements() []*Element {
+	return b.elements
+}
+
+// frame returns the unit direction of the block's axis
+func (b *Block) frame() (float64, float64, bool) {
+	curve := newProfileCurve(b.axis)
+	ux, uy, length := unitVector(curve.x2-curve.x1, curve.y2-curve.y1)
+	return ux, uy, utils.StandardFloatCompare(length, 0) != 0
+}
+
+// local returns the distance of [x, y] from the block's base and the counter-clockwise angle in degrees
+// from the block's axis to the line from the base to [x, y]
+func (b *Block) local(x float64, y float64) (float64, float64) {
+	ux, uy, _ := b.frame()
+	dx, dy := x-b.base.values[0], y-b.base.values[1]
+	angle := math.Atan2((ux*dy)-(uy*dx), (ux*dx)+(uy*dy)) * 180 / math.Pi
+	return math.Hypot(dx, dy), angle
+}
+
+// placement returns the distances of [x, y] from the block's base and from the point length along its axis,
+// and the side of the axis it is on, 1 to the left and -1 to the right
+func (b *Block) placement(x float64, y float64, length float64) (float64, float64, float64) {
+	d, a := b.local(x, y)
+	sin, cos := math.Sincos(a * math.Pi / 180)
+	side := 1.0
+	if sin < 0 {
+		side = -1
+	}
+	return d, math.Hypot((d*cos)-length, d*sin), side
+}
+
+// extent returns the greatest distance of the block's points from its base
+func (b *Block) extent() float64 {
+	extent := 0.0
+	for _, e := range b.elements {
+		for _, p := range patternPoints(e) {
+			d, _ := b.local(p.values[0], p.values[1])
+			extent = math.Max(extent, d)
+		}
+	}
+	return extent
+}
+
+// InsertBlock adds a copy of the block's elements to the sketch with the block's base at [x, y] and its axis
+// rotated angle counter-clockwise from the X axis.
+// The instance has its own construction base point and axis line from it. Each copied point is constrained
+// by its distances from both ends of the axis, the two constraints the graph solver needs to add it to the
+// cluster holding the axis, so the copies are solved with the axis wherever it is placed by constraints on
+// the instance's base and axis. Two distances have a mirrored solution, which the graph solver picks when
+// the axis turns far enough, so each point is also kept on its side of the axis. The sides are checked after
+// the graph solve and only move points, followed by a numeric pass, when one lands on the mirrored solution.
+// Circles keep their radius. Points shared by the block's elements are shared by the copies, and points at
+// the block's base are its base.
+// A parametric instance isn't linked to the block's elements. It only follows later changes to them when
+// it is updated with UpdateBlock, and keeps its shape until then. A rigid instance keeps the shape the
+// block had when it was inserted.
+// It returns the instance added.
+func (s *Sketch) InsertBlock(b *Block, x float64, y float64, angle float64, parametric bool) (*BlockInstance, error) {
+	if b == nil {
+		return nil, errors.New("block must not be nil")
+	}
+	// The block's axis may have been solved to nothing since the block was created
+	if _, _, ok := b.frame(); !ok {
+		return nil, errors.New("block axis must have a length")
+	}
+	x, y, angle = s.units.toLength(x), s.units.toLength(y), s.units.toAngle(angle)
+
+	i := &BlockInstance{block: b, parametric: parametric}
+	place := func(d float64, a float64) (float64, float64) {
+		sin, cos := math.Sincos((angle + a) * math.Pi / 180)
+		return x + (d * cos), y + (d * sin)
+	}
+	move := func(px float64, py float64) (float64, float64) {
+		return place(b.local(px, py))
+	}
+
+	length := math.Max(b.extent(), 1)
+	ax, ay := place(length, 0)
+	i.axis = s.addLine(x, y, ax, ay)
+	i.axis.SetConstruction(true)
+	i.base = i.axis.Start()
+	i.length = s.addDistance(i.axis, nil, length)
+
+	// The copy of each block point
+	points := make(map[uint]*Element)
+	points[b.base.element.GetID()] = i.base
+	for _, e := range b.elements {
+		c := s.copyElement(e, move)
+		i.elements = append(i.elements, c)
+		if c.elementType == Arc {
+			s.clearArcConstraints(c)
+		}
+		sources, copies := patternPoints(e), patternPoints(c)
+		for j, source := range sources {
+			id := source.element.GetID()
+			if p, ok := points[id]; ok {
+				s.AddCoincidentConstraint(p, copies[j])
+				continue
+			}
+			points[id] = copies[j]
+			d, reach, side := b.placement(source.values[0], source.values[1], length)
+			if utils.StandardFloatCompare(d, 0) == 0 {
+				s.AddCoincidentConstraint(i.base, copies[j])
+				continue
+			}
+			i.placements = append(i.placements, blockPlacement{
+				source:   source,
+				distance: s.addDistance(i.base, copies[j], d),
+				reach:    s.addDistance(i.axis.End(), copies[j], reach),
+				side: s.addSideConstraint([]*Element{copies[j], i.axis}, numeric.LoopOrientation,
+					[]el.SketchElement{i.base.element, i.axis.End().element, copies[j].element}, side),
+			})
+		}
+		if e.elementType == Circle {
+			i.radii = append(i.radii, blockRadius{source: e, radius: s.addDistance(c, nil, e.values[2])})
+		}
+	}
+
+	utils.Logger.Info().
+		Int("elements", len(b.elements)).
+		Uint("base", i.base.element.GetID()).
+		Bool("parametric", parametric).
+		Msg("Inserted block")
+	return i, nil
+}
+
+// UpdateBlock updates a parametric block instance after the elements of its block have changed, such as
+// after solving the block's sketch. Points at the block's base when it was inserted stay on the instance's
+// base. If the sketch has been solved, it is solved again.
+func (s *Sketch) UpdateBlock(i *BlockInstance) error {
+	if i == nil || !i.parametric {
+		return errors.New("block instance is not parametric")
+	}
+	b := i.block
+	if _, _, ok := b.frame(); !ok {
+		return errors.New("block axis must have a length")
+	}
+	length := math.Max(b.extent(), 1)
+	for _, p := range i.placements {
+		d, reach, side := b.placement(p.source.values[0], p.source.values[1], length)
+		s.setConstraintValue(p.distance, d)
+		s.setConstraintValue(p.reach, reach)
+		p.side.dataValue, p.side.loop.Value = side, side
+	}
+	for _, r := range i.radii {
+		s.setConstraintValue(r.radius, r.source.values[2])
+	}
+	s.setConstraintValue(i.length, length)
+
+	s.unresolveDerivedConstraints()
+	if s.passes == 0 {
+		return nil
+	}
+	return s.Solve()
+}
+
+// Block returns the block the instance is a copy of
+func (i *BlockInstance) Block() *Block {
+	return i.block
+}
+
+// Elements returns the copies of the block's elements in the order of the block
+func (i *BlockInstance) Elements() []*Element {
+	return i.elements
+}
+
+// Base returns the instance's base point, which places the instance like the block's base point
+func (i *BlockInstance) Base() *Element {
+	return i.base
+}
+
+// Axis returns the instance's construction axis line from its base, which orients the instance like the
+// block's axis
+func (i *BlockInstance) Axis() *Element {
+	return i.axis
+}
+
+// IsParametric returns whether the instance follows changes to its block
+func (i *BlockInstance) IsParametric() bool {
+	return i.parametric
+}
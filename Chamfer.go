@@ -0,0 +1,108 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// AddChamfer cuts the corner shared by the lines l1 and l2 with a line. The chamfer starts a distance d1
+// from the corner along l1 and ends a distance d2 from the corner along l2. The lines are shortened to
+// end at the chamfer and the distances are constrained from the original corner point, which remains at
+// the intersection of the two lines.
+// It returns the chamfer line created.
+func (s *Sketch) AddChamfer(l1 *Element, l2 *Element, d1 float64, d2 float64) (*Element, error) {
+	d1, d2 = s.units.toLength(d1), s.units.toLength(d2)
+	corner, err := chamferCorner(l1, l2, d1)
+	if err != nil {
+		return nil, err
+	}
+	if d2 <= 0 || d2 >= corner.length2 {
+		return nil, errors.New("chamfer distance must be greater than 0 and less than the line length")
+	}
+
+	cornerPoint := l2.children[corner.index2]
+	chamfer, end1, end2 := s.addChamfer(l1, l2, corner, d1, d2)
+	s.addDistance(cornerPoint, end1, d1)
+	s.addDistance(cornerPoint, end2, d2)
+
+	return chamfer, nil
+}
+
+// AddAngleChamfer cuts the corner shared by the lines l1 and l2 with a line. The chamfer starts a distance d
+// from the corner along l1 and leaves l1 at angle v, measured inside the corner. The lines are
+// shortened to end at the chamfer and the distance is constrained from the original corner point, which
+// remains at the intersection of the two lines.
+// It returns the chamfer line created.
+func (s *Sketch) AddAngleChamfer(l1 *Element, l2 *Element, d float64, v float64) (*Element, error) {
+	d, v = s.units.toLength(d), s.units.toAngle(v)
+	corner, err := chamferCorner(l1, l2, d)
+	if err != nil {
+		return nil, err
+	}
+	// The chamfer, l1 and l2 form a triangle. The angle opposite the chamfer's start is what remains.
+	cornerAngle := corner.angle()
+	angle := v * math.Pi / 180
+	if angle <= 0 || utils.StandardFloatCompare(angle+cornerAngle, math.Pi) >= 0 {
+		return nil, errors.New("chamfer angle must be greater than 0 and form a triangle with the corner")
+	}
+	d2 := d * math.Sin(angle) / math.Sin(math.Pi-cornerAngle-angle)
+	if d2 >= corner.length2 {
+		return nil, errors.New("chamfer is too large for the lines")
+	}
+
+	cornerPoint := l2.children[corner.index2]
+	chamfer, end1, _ := s.addChamfer(l1, l2, corner, d, d2)
+	s.addDistance(cornerPoint, end1, d)
+
+	// Angles are measured counter-clockwise from l1's direction to the chamfer's
+	l1x, l1y := corner.u1x, corner.u1y
+	if corner.index1 == 1 {
+		l1x, l1y = -l1x, -l1y
+	}
+	cx, cy := chamfer.values[2]-chamfer.values[0], chamfer.values[3]-chamfer.values[1]
+	signed := math.Atan2((l1x*cy)-(l1y*cx), (l1x*cx)+(l1y*cy))
+	if _, err := s.addAngle(l1, chamfer, signed*180/math.Pi, false); err != nil {
+		return nil, err
+	}
+
+	return chamfer, nil
+}
+
+// chamferCorner returns the corner shared by two lines to be chamfered a distance d along the first line
+func chamferCorner(l1 *Element, l2 *Element, d float64) (lineCorner, error) {
+	if l1 == nil || l2 == nil || l1.elementType != Line || l2.elementType != Line || l1 == l2 {
+		return lineCorner{}, errors.New("incorrect element types for chamfer")
+	}
+	corner, err := findCorner(l1, l2)
+	if err != nil {
+		return corner, err
+	}
+	if utils.StandardFloatCompare(math.Sin(corner.angle()), 0) == 0 {
+		return corner, errors.New("cannot chamfer parallel lines")
+	}
+	if d <= 0 || d >= corner.length1 {
+		return corner, errors.New("chamfer distance must be greater than 0 and less than the line length")
+	}
+	return corner, nil
+}
+
+// addChamfer adds the line from d1 along l1 to d2 along l2 and shortens the lines to meet it
+func (s *Sketch) addChamfer(l1 *Element, l2 *Element, corner lineCorner, d1 float64, d2 float64) (*Element, *Element, *Element) {
+	x1, y1 := corner.alongLine1(d1)
+	x2, y2 := corner.alongLine2(d2)
+	chamfer := s.addLine(x1, y1, x2, y2)
+
+	end1 := s.replaceLineEndpoint(l1, corner.index1, x1, y1)
+	end2 := s.replaceLineEndpoint(l2, corner.index2, x2, y2)
+	s.AddCoincidentConstraint(chamfer.Start(), end1)
+	s.AddCoincidentConstraint(chamfer.End(), end2)
+
+	utils.Logger.Info().
+		Uint("chamfer", chamfer.element.GetID()).
+		Uint("line 1", l1.element.GetID()).
+		Uint("line 2", l2.element.GetID()).
+		Msg("Added Chamfer")
+	return chamfer, end1, end2
+}
@@ -32,7 +32,7 @@ func (s *Sketch) AddCoincidentConstraint(p1 *Element, p2 *Element) *Constraint {
 		p2.id = p1.id
 		return nil
 	}
-	c := s.AddDistanceConstraint(p1, p2, 0)
+	c := s.addDistance(p1, p2, 0)
 	c.constraintType = Coincident
 	return c
 }
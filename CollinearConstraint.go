@@ -0,0 +1,61 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	ic "github.com/marcuswu/dlineate/internal/constraint"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func CollinearConstraint(p1 *Element, p2 *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	constraint.elements = append(constraint.elements, p2)
+	constraint.constraintType = Collinear
+	constraint.state = Resolved
+
+	return constraint
+}
+
+// AddCollinearConstraint adds a constraint keeping the lines p1 and p2 on the same infinite line.
+// Either element may be an axis. Like a parallel constraint, the lines keep their current direction.
+func (s *Sketch) AddCollinearConstraint(p1 *Element, p2 *Element) (*Constraint, error) {
+	if (p1.elementType != Line && p1.elementType != Axis) || (p2.elementType != Line && p2.elementType != Axis) {
+		return nil, errors.New("incorrect element types for collinear constraint")
+	}
+	if p1 == p2 || (p1.elementType == Axis && p2.elementType == Axis) {
+		return nil, errors.New("a collinear constraint requires two different lines")
+	}
+
+	// Lines sharing a, b, c (up to sign) are parallel and share a point
+	var angle big.Float
+	angle.SetPrec(utils.FloatPrecision).SetFloat64(0)
+	if x1, y1, ok := p1.direction(); ok {
+		if x2, y2, ok := p2.direction(); ok && (x1*x2)+(y1*y2) < 0 {
+			angle.SetFloat64(math.Pi)
+		}
+	}
+	line, point := p1, p2.Start()
+	if p2.elementType == Axis {
+		line, point = p2, p1.Start()
+	}
+
+	c := CollinearConstraint(p1, p2)
+	constraint := s.sketch.AddConstraint(ic.Angle, p1.element, p2.element, &angle)
+	p1.constraints = append(p1.constraints, constraint)
+	p2.constraints = append(p2.constraints, constraint)
+	c.constraints = append(c.constraints, constraint)
+
+	constraint = s.addDistanceConstraint(line, point, 0)
+	line.constraints = append(line.constraints, constraint)
+	point.constraints = append(point.constraints, constraint)
+	c.constraints = append(c.constraints, constraint)
+
+	s.constraints = append(s.constraints, c)
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+
+	return c, nil
+}
@@ -0,0 +1,85 @@
+package dlineate
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func ConcentricConstraint(p1 *Element, p2 *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	constraint.elements = append(constraint.elements, p2)
+	constraint.constraintType = Concentric
+	constraint.state = Unresolved
+
+	return constraint
+}
+
+// AddConcentricConstraint adds a constraint keeping the centers of the circles or arcs p1 and p2 at the same location.
+// The centers stay separate points. Once either center is placed, the other is placed on it by the constraint's own
+// internal constraints, so the constraint can be removed on its own.
+// It returns an error if both centers are fixed at different locations.
+func (s *Sketch) AddConcentricConstraint(p1 *Element, p2 *Element) (*Constraint, error) {
+	if p1.Center() == nil || p2.Center() == nil {
+		return nil, errors.New("incorrect element types for concentric constraint")
+	}
+	if p1 == p2 {
+		return nil, errors.New("a concentric constraint requires two different curves")
+	}
+	c1 := p1.Center().element
+	c2 := p2.Center().element
+	if c1.IsFixed() && c2.IsFixed() && utils.StandardBigFloatCompare(c1.AsPoint().DistanceTo(c2), big.NewFloat(0)) != 0 {
+		return nil, errors.New("fixed centers at different locations can't be concentric")
+	}
+
+	c := ConcentricConstraint(p1, p2)
+	s.constraints = append(s.constraints, c)
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+
+	s.resolveConcentricConstraint(c)
+
+	return c, nil
+}
+
+func (s *Sketch) resolveConcentricConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+
+	/*
+	 * Once either center is placed, the other center is placed on it by two constraints of no distance,
+	 * which the graph solver takes together as a point on a point. A center which is already placed is
+	 * left where it is, and the constraints conflict if it is elsewhere.
+	 */
+	c1 := c.elements[0].Center()
+	c2 := c.elements[1].Center()
+	var source, target *Element
+	switch {
+	case s.isPointPlaced(c1):
+		source, target = c1, c2
+	case s.isPointPlaced(c2):
+		source, target = c2, c1
+	default:
+		return false
+	}
+
+	// Each constraint removes one degree of freedom, so the center takes two to be placed
+	for i := 0; i < 2; i++ {
+		constraint := s.addDistanceConstraint(target, source, 0)
+		if constraint == nil {
+			continue
+		}
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("type", c.constraintType.String()).
+			Msg("resolveConcentricConstraint: added constraint")
+		target.constraints = append(target.constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
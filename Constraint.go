@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	c "github.com/marcuswu/dlineate/internal/constraint"
+	"github.com/marcuswu/dlineate/internal/expression"
+	"github.com/marcuswu/dlineate/internal/numeric"
 	"github.com/marcuswu/dlineate/utils"
 )
 
@@ -18,10 +20,33 @@ const (
 	Perpendicular
 	Parallel
 	Tangent
+	Concentric
+	Collinear
+	PointAngle
 
 	// Two pass constraints
 	Ratio
 	Midpoint
+	HorizontalDistance
+	VerticalDistance
+	ArcAngle
+	ArcLength
+	Symmetric
+	ThroughPoints
+	PolygonCenter
+
+	// Numeric constraints
+	MinDistance
+	MaxDistance
+	MinAngle
+	MaxAngle
+	Area
+	Perimeter
+	Side
+
+	// Reference constraints
+	ReferenceDistance
+	ReferenceAngle
 )
 
 func (t ConstraintType) String() string {
@@ -38,10 +63,48 @@ func (t ConstraintType) String() string {
 		return "Parallel"
 	case Tangent:
 		return "Tangent"
+	case Concentric:
+		return "Concentric"
+	case Collinear:
+		return "Collinear"
+	case PointAngle:
+		return "PointAngle"
 	case Ratio:
 		return "Ratio"
 	case Midpoint:
 		return "Midpoint"
+	case HorizontalDistance:
+		return "HorizontalDistance"
+	case VerticalDistance:
+		return "VerticalDistance"
+	case ArcAngle:
+		return "ArcAngle"
+	case ArcLength:
+		return "ArcLength"
+	case Symmetric:
+		return "Symmetric"
+	case ThroughPoints:
+		return "ThroughPoints"
+	case PolygonCenter:
+		return "PolygonCenter"
+	case MinDistance:
+		return "MinDistance"
+	case MaxDistance:
+		return "MaxDistance"
+	case MinAngle:
+		return "MinAngle"
+	case MaxAngle:
+		return "MaxAngle"
+	case Area:
+		return "Area"
+	case Perimeter:
+		return "Perimeter"
+	case Side:
+		return "Side"
+	case ReferenceDistance:
+		return "ReferenceDistance"
+	case ReferenceAngle:
+		return "ReferenceAngle"
 	default:
 		return fmt.Sprintf("%d", int(t))
 	}
@@ -74,6 +137,14 @@ type Constraint struct {
 	constraintType ConstraintType
 	state          ConstraintState
 	dataValue      float64
+	tangentMode    TangentMode
+	inequality     *c.Constraint
+	loop           *numeric.LoopConstraint
+	sides          []*numeric.LoopConstraint // keep derived constraints' internal constraints on one solution
+	err            error                     // why a derived constraint's internal constraints can't be added
+	active         bool
+	supplementary  bool
+	expression     *expression.Expression
 }
 
 func emptyConstraint() *Constraint {
@@ -133,13 +204,28 @@ Coincident constraint -- points, point & line, point & curve, line & curve
 Angle -- two lines
 Perpendicular -- two lines
 Parallel -- two lines
+Collinear -- two lines (parallel with a shared point)
+Point angle -- three points (an angle between hidden helper lines)
 
 Two Pass Constraints
 -------------
 Equal constraint -- 2nd pass constraint
 Distance ratio constraint -- 2nd pass constraint
 Midpoint -- 2nd pass constraint (equal distances to either end of the line or arc)
+Horizontal / vertical distance -- 2nd pass constraint (distance from an axis once the other point is solved)
+Arc angle / arc length -- 2nd pass constraint (distance between the arc's start and end and the side of it the center is on once the radius is known)
 Tangent -- line and curve
-Symmetric -- TODO
+Concentric -- 2nd pass constraint (two zero distances between the centers once either center is solved)
+Symmetric -- 2nd pass constraint (a perpendicular helper line and a distance from the line once either point is solved)
+Through points -- 2nd pass constraint (distances from a circle's or arc's center to two of its points once all three are placed)
+Polygon center -- 2nd pass constraint (distances from a regular polygon's center to the ends of a side once its length or radius is known)
+
+Numeric Constraints
+-------------
+Minimum / maximum distance and angle -- enforced by the numeric solver after the graph solve
+
+Reference Constraints
+-------------
+Reference distance and angle -- measured from the solved elements, no internal constraints
 
 */
@@ -69,7 +69,13 @@ func (s *Sketch) addDistanceConstraint(p1 *Element, p2 *Element, v float64) *ic.
 }
 
 func (s *Sketch) AddDistanceConstraint(p1 *Element, p2 *Element, v float64) *Constraint {
+	return s.addDistance(p1, p2, s.units.toLength(v))
+}
+
+// addDistance adds a distance constraint with the distance v in millimeters
+func (s *Sketch) addDistance(p1 *Element, p2 *Element, v float64) *Constraint {
 	c := DistanceConstraint(p1, p2)
+	c.dataValue = v
 
 	constraint := s.addDistanceConstraint(p1, p2, v)
 	if constraint != nil {
@@ -45,14 +45,18 @@ func (et ElementType) String() string {
 }
 
 type Element struct {
-	id          uint
-	values      []float64
-	elementType ElementType
-	constraints []*c.Constraint
-	element     el.SketchElement
-	children    []*Element
-	isChild     bool
-	valuePass   int
+	id           uint
+	values       []float64
+	elementType  ElementType
+	constraints  []*c.Constraint
+	element      el.SketchElement
+	children     []*Element
+	isChild      bool
+	hidden       bool
+	construction bool
+	external     *externalSource
+	units        *unitSystem
+	valuePass    int
 }
 
 func emptyElement() *Element {
@@ -61,6 +65,8 @@ func emptyElement() *Element {
 	ec.constraints = make([]*c.Constraint, 0)
 	ec.children = make([]*Element, 0)
 	ec.isChild = false
+	ec.hidden = false
+	ec.construction = false
 	ec.valuePass = 0
 	return ec
 }
@@ -69,6 +75,21 @@ func (e *Element) ID() uint {
 	return e.id
 }
 
+// SetConstruction marks an element (and its child elements) as construction geometry.
+// Construction geometry takes part in constraints but is not part of the sketch's outline. It is
+// drawn dashed in images and left out of profiles.
+func (e *Element) SetConstruction(construction bool) {
+	e.construction = construction
+	for _, c := range e.children {
+		c.construction = construction
+	}
+}
+
+// IsConstruction returns whether an element is construction geometry
+func (e *Element) IsConstruction() bool {
+	return e.construction
+}
+
 func (e *Element) valuesFromSketch(s *Sketch) error {
 	switch e.elementType {
 	case Point:
@@ -92,15 +113,16 @@ func (e *Element) valuesFromSketch(s *Sketch) error {
 			Circle radius is determined either by
 			  * a distance constraint against the Circle
 			  * a coincident constraint against a Circle with the location of the center constrained
+			  * a ratio constraint against another Circle, such as an equal constraint
+			  * a through points constraint placing the center from points on the circle
 		*/
 		var err error = nil
 		c := e.children[0].element.AsPoint()
 		e.values[0], _ = c.GetX().Float64()
 		e.values[1], _ = c.GetY().Float64()
-		// find distance constraint on e
-		constraint, err := s.findConstraint(Distance, e)
+		constraint, err := e.radiusConstraint(s)
 		if err != nil {
-			constraint, err = s.findConstraint(Coincident, e)
+			constraint, err = s.findConstraint(Ratio, e)
 		}
 		if err != nil {
 			return err
@@ -132,6 +154,26 @@ func (e *Element) getCircleRadius(s *Sketch, c *Constraint) (float64, error) {
 	if c.constraintType == Distance && len(c.elements) == 1 && c.elements[0].id == e.id {
 		return c.dataValue, nil
 	}
+	if c.constraintType == Ratio {
+		// The ratio scales the radius of the other circle. p2's radius = p1's radius * constraint value
+		other, scale := c.elements[0], c.dataValue
+		if other.id == e.id {
+			other, scale = c.elements[1], 1/c.dataValue
+		}
+		if other.elementType != Circle {
+			return 0, errors.New("ratio for circle radius must be against another circle")
+		}
+		constraint, err := other.radiusConstraint(s)
+		if err != nil {
+			return 0, err
+		}
+		radius, err := other.getCircleRadius(s, constraint)
+		return radius * scale, err
+	}
+	if c.constraintType == ThroughPoints {
+		radius, _ := c.constraints[0].Value.Float64()
+		return radius, nil
+	}
 	if c.constraintType == Coincident {
 		constraint := c.constraints[0]
 		other, _ := s.sketch.GetElement(constraint.Element1)
@@ -143,11 +185,34 @@ func (e *Element) getCircleRadius(s *Sketch, c *Constraint) (float64, error) {
 		return dist, nil
 	}
 
-	return 0, errors.New("Constraint type for circle radius must be Distance or Coincident")
+	return 0, errors.New("Constraint type for circle radius must be Distance, Coincident, ThroughPoints or Ratio")
 }
 
+// radiusConstraint returns the distance, coincident or through points constraint which sets a circle's radius
+func (e *Element) radiusConstraint(s *Sketch) (*Constraint, error) {
+	constraint, err := s.findConstraint(Distance, e)
+	if err != nil {
+		constraint, err = s.findConstraint(Coincident, e)
+	}
+	if err != nil {
+		constraint, err = s.findConstraint(ThroughPoints, e)
+	}
+	return constraint, err
+}
+
+// Values returns the values of an element in the sketch's length unit
+// Points are [x, y], lines are [x1, y1, x2, y2], circles are [x, y, radius] and arcs are
+// [center x, center y, start x, start y, end x, end y]. Axes are [a, b, c] of the line ax + by + c = 0.
 func (e *Element) Values() []float64 {
-	return e.values
+	values := make([]float64, len(e.values))
+	for i, v := range e.values {
+		values[i] = e.units.fromLength(v)
+	}
+	if e.elementType == Axis {
+		// Only c is a length
+		values[0], values[1] = e.values[0], e.values[1]
+	}
+	return values
 }
 
 func (e *Element) ConstraintLevel() el.ConstraintLevel {
@@ -280,6 +345,10 @@ func (e *Element) minMaxXY() (float64, float64, float64, float64) {
 }
 
 func (e *Element) DrawToSVG(s *Sketch, canvas *svg.SVG, mult float64) {
+	// External geometry belongs to the 3D model and isn't exported with the sketch
+	if e.hidden || e.external != nil {
+		return
+	}
 	style := "stroke:blue"
 	if e.elementType != Axis && e.ConstraintLevel() == el.FullyConstrained {
 		style = "stroke:black"
@@ -287,6 +356,17 @@ func (e *Element) DrawToSVG(s *Sketch, canvas *svg.SVG, mult float64) {
 	if e.elementType != Axis && e.ConstraintLevel() == el.OverConstrained {
 		style = "stroke:red"
 	}
+	if e.construction {
+		// Construction geometry is drawn lighter and dashed
+		style = "stroke:lightsteelblue"
+		if e.elementType != Axis && e.ConstraintLevel() == el.FullyConstrained {
+			style = "stroke:silver"
+		}
+		if e.elementType != Axis && e.ConstraintLevel() == el.OverConstrained {
+			style = "stroke:lightcoral"
+		}
+		style += ";stroke-dasharray:2,1"
+	}
 	style += ";stroke-width:0.5;fill:none"
 	switch e.elementType {
 	case Point:
@@ -358,6 +438,19 @@ func (e *Element) End() *Element {
 	return e.children[1]
 }
 
+// direction returns the direction of travel of a line (start to end) or axis
+// It returns false if the element has no direction
+func (e *Element) direction() (float64, float64, bool) {
+	switch e.elementType {
+	case Line:
+		return e.values[2] - e.values[0], e.values[3] - e.values[1], true
+	case Axis:
+		// Axis values are a, b, c of the line ax + by + c = 0 with a direction of (-b, a)
+		return -e.values[1], e.values[0], true
+	}
+	return 0, 0, false
+}
+
 func (e *Element) PointVerticalFrom(x, y float64) (float64, float64, bool) {
 	if e.elementType != Line && e.elementType != Axis {
 		log.Debug().Msg("element is not a line or axis")
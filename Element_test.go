@@ -1,11 +1,13 @@
 package dlineate
 
 import (
+	"math"
 	"math/big"
 	"testing"
 
 	"github.com/marcuswu/dlineate/internal/constraint"
 	"github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -150,3 +152,78 @@ func TestStartCenterEndEdgeCases(t *testing.T) {
 	e = p.End()
 	assert.Nil(t, e, "Point should not have a end")
 }
+
+func TestSetConstruction(t *testing.T) {
+	s := NewSketch()
+	l := s.AddLine(0, 0, 1, 1)
+	c := s.AddCircle(0, 0, 1)
+	assert.False(t, l.IsConstruction(), "Elements are not construction geometry by default")
+
+	l.SetConstruction(true)
+	assert.True(t, l.IsConstruction(), "Line is construction geometry")
+	assert.True(t, l.Start().IsConstruction(), "Line start is construction geometry")
+	assert.True(t, l.End().IsConstruction(), "Line end is construction geometry")
+	assert.False(t, c.IsConstruction(), "Other elements are unaffected")
+
+	l.SetConstruction(false)
+	assert.False(t, l.IsConstruction(), "Line is no longer construction geometry")
+	assert.False(t, l.Start().IsConstruction(), "Line start is no longer construction geometry")
+}
+
+func TestElementValueUnits(t *testing.T) {
+	s := NewSketch()
+	assert.Equal(t, Millimeter, s.LengthUnit(), "Sketches default to millimeters")
+	assert.Equal(t, Degree, s.AngleUnit(), "Sketches default to degrees")
+
+	s.SetUnits(Inch, Radian)
+	l := s.AddLine(0, 0, 2, 1)
+	assert.InDeltaSlice(t, []float64{0, 0, 2, 1}, l.Values(), 1e-9, "Line values are in inches")
+	assert.InDeltaSlice(t, []float64{0, 0, 50.8, 25.4}, l.values, 1e-9, "Line values are stored in millimeters")
+	assert.InDeltaSlice(t, []float64{2, 1}, l.End().Values(), 1e-9, "Child values are in inches")
+	assert.InDeltaSlice(t, []float64{0, -1, 0}, s.XAxis.Values(), 1e-9, "Axis direction is unitless")
+
+	s.SetUnits(Centimeter, Degree)
+	assert.InDeltaSlice(t, []float64{0, 0, 5.08, 2.54}, l.Values(), 1e-9, "Values follow the sketch units")
+
+	assert.InDelta(t, 2.54, s.Length(Inches(1)), 1e-9, "Typed lengths convert to the sketch unit")
+	assert.InDelta(t, 150, s.Length(Meters(1.5)), 1e-9, "Typed lengths convert to the sketch unit")
+	assert.InDelta(t, 180, s.Angle(Radians(math.Pi)), 1e-9, "Typed angles convert to the sketch unit")
+	assert.InDelta(t, math.Pi/6, Degrees(30).In(Radian), 1e-9, "Typed angles convert to any unit")
+	assert.InDelta(t, 1, Millimeters(25.4).In(Inch), 1e-9, "Typed lengths convert to any unit")
+}
+
+func TestValues3D(t *testing.T) {
+	s := NewSketch()
+	s.UseWorkplane(NewWorkPlane(NewVector(0, 0, 5), NewVector(0, 1, 0), NewVector(0, 0, 1)))
+	l := s.AddLine(1, 2, 3, 4)
+	c := s.AddCircle(1, 1, 2)
+	a := s.AddArc(0, 0, 0, 1, 1, 0)
+
+	assert.Equal(t, []*Vector3D{NewVector(0, 1, 7), NewVector(0, 3, 9)}, l.Values3D(s), "Line ends on the YZ plane")
+	assert.Equal(t, []*Vector3D{NewVector(0, 1, 6)}, c.Values3D(s), "Circle center on the YZ plane")
+	assert.Equal(t, []*Vector3D{NewVector(0, 0, 5), NewVector(0, 0, 6), NewVector(0, 1, 5)}, a.Values3D(s), "Arc points on the YZ plane")
+	assert.Equal(t, []*Vector3D{NewVector(0, 0, 5), NewVector(0, 1, 5)}, s.XAxis.Values3D(s), "X axis follows the x direction")
+}
+
+func TestAddExternalGeometry(t *testing.T) {
+	s := NewSketch()
+	s.UseWorkplane(NewWorkPlane(NewVector(0, 0, 5), NewVector(0, 1, 0), NewVector(0, 0, 1)))
+	p := s.AddExternalPoint(NewVector(3, 1, 7))
+	l := s.AddExternalLine(NewVector(-2, 1, 6), NewVector(4, 5, 7))
+	c := s.AddCircle(0, 0, 1)
+
+	assert.True(t, p.IsExternal(), "Point is external")
+	assert.True(t, l.IsExternal(), "Line is external")
+	assert.True(t, l.Start().IsExternal(), "Line start is external")
+	assert.False(t, c.IsExternal(), "Other elements are not external")
+	assert.InDeltaSlice(t, []float64{1, 2}, p.Values(), utils.StandardCompare, "Point is projected onto the workplane")
+	assert.InDeltaSlice(t, []float64{1, 1, 5, 2}, l.Values(), utils.StandardCompare, "Line is projected onto the workplane")
+	assert.True(t, p.element.IsFixed(), "External points are fixed")
+	assert.True(t, l.element.IsFixed(), "External lines are fixed")
+	assert.True(t, l.End().element.IsFixed(), "External line ends are fixed")
+
+	assert.NotNil(t, s.UpdateExternal(c, NewVector(0, 0, 0)), "Only external geometry can be updated")
+	assert.NotNil(t, s.UpdateExternal(l, NewVector(0, 0, 0)), "Lines are updated with two points")
+	assert.Nil(t, s.UpdateExternal(p, NewVector(0, 2, 8)), "Point is updated")
+	assert.InDeltaSlice(t, []float64{2, 3}, p.Values(), utils.StandardCompare, "Point is projected again")
+}
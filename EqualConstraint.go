@@ -1,5 +1,7 @@
 package dlineate
 
+// AddEqualConstraint adds a constraint keeping the magnitudes of p1 and p2 equal -- the lengths of lines and
+// arcs or the radii of circles.
 func (s *Sketch) AddEqualConstraint(p1 *Element, p2 *Element) *Constraint {
 	c := s.AddRatioConstraint(p1, p2, 1)
 	return c
@@ -0,0 +1,133 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	ic "github.com/marcuswu/dlineate/internal/constraint"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// externalSource records the 3D points an external element was projected from
+type externalSource struct {
+	points []*Vector3D
+	// pins are the internal constraints placing an external point relative to the axes
+	pins []*ic.Constraint
+}
+
+// AddExternalPoint adds a point projected from the 3D point v onto the sketch's workplane as fixed reference
+// geometry. External elements can be constrained against but are not moved by the solver and are not exported.
+// It returns the point element created.
+func (s *Sketch) AddExternalPoint(v *Vector3D) *Element {
+	x, y := s.projectExternal(v)
+	p := s.addPoint(x, y)
+	s.addExternalPin(p)
+	p.external.points = []*Vector3D{v}
+	s.MakeFixed(p)
+
+	utils.Logger.Info().
+		Uint("point", p.element.GetID()).
+		Msg("Added External Point")
+	return p
+}
+
+// AddExternalLine adds a line projected from the 3D edge between a and b onto the sketch's workplane as fixed
+// reference geometry. External elements can be constrained against but are not moved by the solver and are
+// not exported.
+// It returns the line element created.
+func (s *Sketch) AddExternalLine(a *Vector3D, b *Vector3D) *Element {
+	x1, y1 := s.projectExternal(a)
+	x2, y2 := s.projectExternal(b)
+	l := s.addLine(x1, y1, x2, y2)
+	l.external = &externalSource{points: []*Vector3D{a, b}}
+	for i, child := range l.children {
+		s.addExternalPin(child)
+		child.external.points = []*Vector3D{l.external.points[i]}
+	}
+	s.MakeFixed(l)
+
+	utils.Logger.Info().
+		Uint("line", l.element.GetID()).
+		Msg("Added External Line")
+	return l
+}
+
+// IsExternal returns whether an element is reference geometry projected from 3D
+func (e *Element) IsExternal() bool {
+	return e.external != nil
+}
+
+// UpdateExternal moves an external element after its 3D source has moved. Points take one 3D point and
+// lines take their two end points. If the sketch has been solved, it is solved again.
+func (s *Sketch) UpdateExternal(e *Element, v ...*Vector3D) error {
+	if e == nil || e.external == nil || e.isChild {
+		return errors.New("element is not external geometry")
+	}
+	if len(v) != len(e.external.points) {
+		return errors.New("incorrect number of points for external geometry")
+	}
+
+	switch e.elementType {
+	case Point:
+		s.moveExternalPoint(e, v[0])
+	case Line:
+		for i, child := range e.children {
+			s.moveExternalPoint(child, v[i])
+		}
+		e.values = append(append(e.values[:0], e.children[0].values...), e.children[1].values...)
+		a, b, c := utils.BigFloatLineFromPoints(e.values[0], e.values[1], e.values[2], e.values[3])
+		if l, ok := s.sketch.GetElement(e.element.GetID()); ok {
+			line := l.AsLine()
+			line.SetA(a)
+			line.SetB(b)
+			line.SetC(c)
+			line.Normalize()
+		}
+	}
+	e.external.points = v
+
+	s.unresolveDerivedConstraints()
+	if s.passes == 0 {
+		return nil
+	}
+	return s.Solve()
+}
+
+// projectExternal returns the sketch coordinates in millimeters of a 3D point projected onto the workplane
+func (s *Sketch) projectExternal(v *Vector3D) (float64, float64) {
+	x, y := s.ProjectTo2D(v)
+	return s.units.toLength(x), s.units.toLength(y)
+}
+
+// addExternalPin places an external point relative to the axes so it is part of the origin's cluster when solving
+func (s *Sketch) addExternalPin(p *Element) {
+	p.external = &externalSource{}
+	for _, axis := range []*Element{s.XAxis, s.YAxis} {
+		var value big.Float
+		value.SetPrec(utils.FloatPrecision).SetFloat64(0)
+		constraint := s.sketch.AddConstraint(ic.Distance, p.element, axis.element, &value)
+		p.constraints = append(p.constraints, constraint)
+		p.external.pins = append(p.external.pins, constraint)
+	}
+	s.setExternalPins(p)
+}
+
+// setExternalPins updates the distances from an external point to the X and Y axes
+func (s *Sketch) setExternalPins(p *Element) {
+	p.external.pins[0].Value.SetFloat64(math.Abs(p.values[1]))
+	p.external.pins[1].Value.SetFloat64(math.Abs(p.values[0]))
+}
+
+// moveExternalPoint moves an external point to the projection of v
+func (s *Sketch) moveExternalPoint(p *Element, v *Vector3D) {
+	x, y := s.projectExternal(v)
+	p.values[0], p.values[1] = x, y
+	if e, ok := s.sketch.GetElement(p.element.GetID()); ok {
+		point := e.AsPoint()
+		point.X.SetFloat64(x)
+		point.Y.SetFloat64(y)
+	}
+	p.external.points = []*Vector3D{v}
+	s.setExternalPins(p)
+}
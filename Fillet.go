@@ -0,0 +1,166 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// AddFillet rounds the corner shared by the lines l1 and l2 with an arc of the given radius.
+// The lines are shortened to end at the points where the arc is tangent to them. The arc is
+// constrained tangent to both lines with its ends coincident with the new line ends, so it follows
+// the corner when the sketch is solved. The original corner point remains at the intersection of
+// the two lines.
+// It returns the arc element created.
+func (s *Sketch) AddFillet(l1 *Element, l2 *Element, radius float64) (*Element, error) {
+	if l1 == nil || l2 == nil || l1.elementType != Line || l2.elementType != Line || l1 == l2 {
+		return nil, errors.New("incorrect element types for fillet")
+	}
+	radius = s.units.toLength(radius)
+	if radius <= 0 {
+		return nil, errors.New("fillet radius must be greater than 0")
+	}
+	corner, err := findCorner(l1, l2)
+	if err != nil {
+		return nil, err
+	}
+
+	// The arc center is on the corner's bisector and touches each line a distance t from the corner
+	halfAngle := corner.angle() / 2
+	if utils.StandardFloatCompare(math.Sin(halfAngle*2), 0) == 0 {
+		return nil, errors.New("cannot fillet parallel lines")
+	}
+	t := radius / math.Tan(halfAngle)
+	if t >= corner.length1 || t >= corner.length2 {
+		return nil, errors.New("fillet radius is too large for the lines")
+	}
+	t1x, t1y := corner.alongLine1(t)
+	t2x, t2y := corner.alongLine2(t)
+	bisX, bisY, _ := unitVector(corner.u1x+corner.u2x, corner.u1y+corner.u2y)
+	cx := corner.x + (bisX * radius / math.Sin(halfAngle))
+	cy := corner.y + (bisY * radius / math.Sin(halfAngle))
+
+	// Arcs run clockwise from start to end
+	var arc, arcEnd1, arcEnd2 *Element
+	if ((t1x-cx)*(t2y-cy))-((t1y-cy)*(t2x-cx)) > 0 {
+		arc = s.addArc(cx, cy, t2x, t2y, t1x, t1y)
+		arcEnd1, arcEnd2 = arc.End(), arc.Start()
+	} else {
+		arc = s.addArc(cx, cy, t1x, t1y, t2x, t2y)
+		arcEnd1, arcEnd2 = arc.Start(), arc.End()
+	}
+	s.addDistance(arc, nil, radius)
+
+	end1 := s.replaceLineEndpoint(l1, corner.index1, t1x, t1y)
+	end2 := s.replaceLineEndpoint(l2, corner.index2, t2x, t2y)
+	s.AddCoincidentConstraint(arcEnd1, end1)
+	s.AddCoincidentConstraint(arcEnd2, end2)
+	if _, err := s.AddTangentConstraint(l1, arc); err != nil {
+		return nil, err
+	}
+	if _, err := s.AddTangentConstraint(l2, arc); err != nil {
+		return nil, err
+	}
+
+	utils.Logger.Info().
+		Uint("arc", arc.element.GetID()).
+		Uint("line 1", l1.element.GetID()).
+		Uint("line 2", l2.element.GetID()).
+		Msg("Added Fillet")
+	return arc, nil
+}
+
+// lineCorner describes the corner shared by two lines from their current values
+type lineCorner struct {
+	// index of the corner point in each line's children
+	index1, index2 int
+	// location of the corner
+	x, y float64
+	// unit vectors from the corner along each line
+	u1x, u1y, u2x, u2y float64
+	// length of each line
+	length1, length2 float64
+}
+
+// findCorner returns the corner shared by the lines l1 and l2
+func findCorner(l1 *Element, l2 *Element) (lineCorner, error) {
+	var corner lineCorner
+	i1, i2, ok := sharedCorner(l1, l2)
+	if !ok {
+		return corner, errors.New("lines must share a corner point")
+	}
+	corner.index1, corner.index2 = i1, i2
+	corner.x, corner.y = pointLocation(l1.children[i1])
+	ax, ay := pointLocation(l1.children[1-i1])
+	bx, by := pointLocation(l2.children[1-i2])
+	corner.u1x, corner.u1y, corner.length1 = unitVector(ax-corner.x, ay-corner.y)
+	corner.u2x, corner.u2y, corner.length2 = unitVector(bx-corner.x, by-corner.y)
+	if utils.StandardFloatCompare(corner.length1, 0) == 0 || utils.StandardFloatCompare(corner.length2, 0) == 0 {
+		return corner, errors.New("lines at a corner must have a length")
+	}
+	return corner, nil
+}
+
+// angle returns the angle in radians between the lines at the corner (0 to pi)
+func (c lineCorner) angle() float64 {
+	return math.Acos(math.Max(-1, math.Min(1, (c.u1x*c.u2x)+(c.u1y*c.u2y))))
+}
+
+// alongLine1 returns the point a distance d from the corner along the first line
+func (c lineCorner) alongLine1(d float64) (float64, float64) {
+	return c.x + (c.u1x * d), c.y + (c.u1y * d)
+}
+
+// alongLine2 returns the point a distance d from the corner along the second line
+func (c lineCorner) alongLine2(d float64) (float64, float64) {
+	return c.x + (c.u2x * d), c.y + (c.u2y * d)
+}
+
+// sharedCorner returns the index of the endpoint of each line which is the same point
+func sharedCorner(l1 *Element, l2 *Element) (int, int, bool) {
+	for i1, p1 := range l1.children {
+		for i2, p2 := range l2.children {
+			if p1.element.GetID() == p2.element.GetID() {
+				return i1, i2, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// pointLocation returns the current location of a point
+func pointLocation(e *Element) (float64, float64) {
+	p := e.element.AsPoint()
+	x, _ := p.GetX().Float64()
+	y, _ := p.GetY().Float64()
+	return x, y
+}
+
+// unitVector returns the normalized vector [x, y] and its original length
+func unitVector(x float64, y float64) (float64, float64, float64) {
+	length := math.Sqrt((x * x) + (y * y))
+	if length == 0 {
+		return 0, 0, 0
+	}
+	return x / length, y / length, length
+}
+
+// replaceLineEndpoint gives a line a new start (index 0) or end (index 1) point at [x, y].
+// The previous endpoint remains in the sketch constrained to the line.
+func (s *Sketch) replaceLineEndpoint(line *Element, index int, x float64, y float64) *Element {
+	p := s.addPoint(x, y)
+	p.isChild = true
+	p.construction = line.construction
+	le := line.element.AsLine()
+	if index == 0 {
+		le.Start = p.element.AsPoint()
+	} else {
+		le.End = p.element.AsPoint()
+	}
+	line.children[index] = p
+	line.values[index*2] = x
+	line.values[(index*2)+1] = y
+	s.addDistance(line, p, 0.0)
+	return p
+}
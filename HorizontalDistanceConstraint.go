@@ -0,0 +1,208 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func HorizontalDistanceConstraint(p1 *Element, p2 *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	constraint.elements = append(constraint.elements, p2)
+	constraint.constraintType = HorizontalDistance
+	constraint.state = Unresolved
+
+	return constraint
+}
+
+func VerticalDistanceConstraint(p1 *Element, p2 *Element) *Constraint {
+	c := HorizontalDistanceConstraint(p1, p2)
+	c.constraintType = VerticalDistance
+	return c
+}
+
+// AddHorizontalDistanceConstraint adds a constraint setting the distance along the X axis from p1 to p2 to v.
+// p1 and p2 may be two points or a point and a line or axis. For a line, the distance is measured
+// horizontally from the point to the line.
+// Between two points v is signed, so p2 is v to the right of p1. From a line the sign of v is not enforced --
+// the side the point ends up on is chosen from its current position.
+func (s *Sketch) AddHorizontalDistanceConstraint(p1 *Element, p2 *Element, v float64) (*Constraint, error) {
+	if !validAxisDistanceElements(p1, p2) || p1 == s.XAxis || p2 == s.XAxis {
+		return nil, errors.New("incorrect element types for horizontal distance constraint")
+	}
+	return s.addAxisDistanceConstraint(HorizontalDistanceConstraint(p1, p2), s.units.toLength(v)), nil
+}
+
+// AddVerticalDistanceConstraint adds a constraint setting the distance along the Y axis from p1 to p2 to v.
+// p1 and p2 may be two points or a point and a line or axis. For a line, the distance is measured
+// vertically from the point to the line.
+// Between two points v is signed, so p2 is v above p1. From a line the sign of v is not enforced --
+// the side the point ends up on is chosen from its current position.
+func (s *Sketch) AddVerticalDistanceConstraint(p1 *Element, p2 *Element, v float64) (*Constraint, error) {
+	if !validAxisDistanceElements(p1, p2) || p1 == s.YAxis || p2 == s.YAxis {
+		return nil, errors.New("incorrect element types for vertical distance constraint")
+	}
+	return s.addAxisDistanceConstraint(VerticalDistanceConstraint(p1, p2), s.units.toLength(v)), nil
+}
+
+// validAxisDistanceElements returns whether the elements are two points or a point and a line or axis
+func validAxisDistanceElements(p1 *Element, p2 *Element) bool {
+	if p1 == nil || p2 == nil {
+		return false
+	}
+	if p1.elementType != Point {
+		p1, p2 = p2, p1
+	}
+	if p1.elementType != Point {
+		return false
+	}
+	return p2.elementType == Point || p2.elementType == Line || p2.elementType == Axis
+}
+
+func (s *Sketch) addAxisDistanceConstraint(c *Constraint, v float64) *Constraint {
+	c.dataValue = v
+	p1 := c.elements[0]
+	p2 := c.elements[1]
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+	s.constraints = append(s.constraints, c)
+
+	s.resolveAxisDistanceConstraint(c)
+
+	return c
+}
+
+// isPointPlaced returns whether a point's location is known -- it is either fixed or solved
+func (s *Sketch) isPointPlaced(e *Element) bool {
+	return e.element.IsFixed() || s.isElementSolved(e)
+}
+
+// measuredCoordinate returns the coordinate of a point along the direction a constraint measures
+func (s *Sketch) measuredCoordinate(c *Constraint, e *Element) float64 {
+	current, ok := s.sketch.GetElement(e.element.GetID())
+	if !ok {
+		current = e.element
+	}
+	value := current.AsPoint().GetX()
+	if c.constraintType == VerticalDistance {
+		value = current.AsPoint().GetY()
+	}
+	v, _ := value.Float64()
+	return v
+}
+
+func (s *Sketch) resolveAxisDistanceConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+
+	point := c.elements[0]
+	other := c.elements[1]
+	if point.elementType != Point {
+		point, other = other, point
+	}
+
+	if other.elementType == Point {
+		return s.resolvePointAxisDistance(c)
+	}
+
+	return s.resolveLineAxisDistance(c, point, other)
+}
+
+func (s *Sketch) resolvePointAxisDistance(c *Constraint) bool {
+	/*
+	 * Once one point is placed, the other is a known distance from the axis perpendicular
+	 * to the direction being measured (the Y axis for horizontal distances). The distance has
+	 * no sign, so a side constraint keeps the point on the side of the axis its coordinate is on.
+	 */
+	axis := s.YAxis
+	if c.constraintType == VerticalDistance {
+		axis = s.XAxis
+	}
+
+	p1 := c.elements[0]
+	p2 := c.elements[1]
+	var target *Element
+	var dist float64
+	switch {
+	case s.isPointPlaced(p1):
+		target = p2
+		dist = s.measuredCoordinate(c, p1) + c.dataValue
+	case s.isPointPlaced(p2):
+		target = p1
+		dist = s.measuredCoordinate(c, p2) - c.dataValue
+	default:
+		return false
+	}
+
+	constraint := s.addDistanceConstraint(target, axis, math.Abs(dist))
+	if constraint != nil {
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("type", c.constraintType.String()).
+			Msg("resolveAxisDistanceConstraint: added constraint")
+		target.constraints = append(target.constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	x, y := dist, 0.0
+	if c.constraintType == VerticalDistance {
+		x, y = 0, dist
+	}
+	if measure, points, side := s.lineSide(axis, target, x, y); side != 0 {
+		s.addDerivedSide(c, measure, points, side)
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
+
+func (s *Sketch) resolveLineAxisDistance(c *Constraint, point *Element, line *Element) bool {
+	/*
+	 * The distance measured along an axis from a point to a line is the perpendicular distance
+	 * scaled by the angle between the line and that axis, so the line's direction must be known
+	 */
+	if line.elementType == Line && !(s.isPointPlaced(line.children[0]) && s.isPointPlaced(line.children[1])) {
+		return false
+	}
+
+	current, ok := s.sketch.GetElement(line.element.GetID())
+	if !ok {
+		current = line.element
+	}
+	direction := current.AsLine().Direction()
+	magnitude, _ := direction.Magnitude().Float64()
+	if magnitude == 0 {
+		return false
+	}
+	// For horizontal distances the perpendicular distance is scaled by the sine of the line's angle
+	// with the X axis. For vertical distances it is the cosine.
+	component := direction.GetY()
+	if c.constraintType == VerticalDistance {
+		component = direction.GetX()
+	}
+	scale, _ := component.Float64()
+	scale = math.Abs(scale / magnitude)
+	if utils.StandardFloatCompare(scale, 0) == 0 {
+		utils.Logger.Error().
+			Str("type", c.constraintType.String()).
+			Uint("line", line.element.GetID()).
+			Msg("Line is parallel to the measured direction")
+		return false
+	}
+
+	constraint := s.addDistanceConstraint(point, line, math.Abs(c.dataValue)*scale)
+	if constraint != nil {
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("type", c.constraintType.String()).
+			Msg("resolveAxisDistanceConstraint: added constraint")
+		point.constraints = append(point.constraints, constraint)
+		line.constraints = append(line.constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
@@ -0,0 +1,163 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	ic "github.com/marcuswu/dlineate/internal/constraint"
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/solver"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func InequalityConstraint(p1 *Element, p2 *Element, ctype ConstraintType) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	if p2 != nil {
+		constraint.elements = append(constraint.elements, p2)
+	}
+	constraint.constraintType = ctype
+	constraint.state = Resolved
+
+	return constraint
+}
+
+// AddMinDistanceConstraint adds a constraint keeping the distance between p1 and p2 at least v.
+// If p2 is nil, p1 must be a line and its length is constrained. Circles and arcs are measured from their center.
+// Inequalities are enforced by the numeric solver after the other constraints are solved.
+func (s *Sketch) AddMinDistanceConstraint(p1 *Element, p2 *Element, v float64) (*Constraint, error) {
+	return s.addDistanceInequality(p1, p2, s.units.toLength(v), MinDistance)
+}
+
+// AddMaxDistanceConstraint adds a constraint keeping the distance between p1 and p2 at most v.
+// If p2 is nil, p1 must be a line and its length is constrained. Circles and arcs are measured from their center.
+// Inequalities are enforced by the numeric solver after the other constraints are solved.
+func (s *Sketch) AddMaxDistanceConstraint(p1 *Element, p2 *Element, v float64) (*Constraint, error) {
+	return s.addDistanceInequality(p1, p2, s.units.toLength(v), MaxDistance)
+}
+
+// AddMinAngleConstraint adds a constraint keeping the angle between the lines p1 and p2 at least v.
+// The angle is between the directions of the lines regardless of the direction of rotation (0 to 180 degrees).
+// Inequalities are enforced by the numeric solver after the other constraints are solved.
+func (s *Sketch) AddMinAngleConstraint(p1 *Element, p2 *Element, v float64) (*Constraint, error) {
+	return s.addAngleInequality(p1, p2, s.units.toAngle(v), MinAngle)
+}
+
+// AddMaxAngleConstraint adds a constraint keeping the angle between the lines p1 and p2 at most v.
+// The angle is between the directions of the lines regardless of the direction of rotation (0 to 180 degrees).
+// Inequalities are enforced by the numeric solver after the other constraints are solved.
+func (s *Sketch) AddMaxAngleConstraint(p1 *Element, p2 *Element, v float64) (*Constraint, error) {
+	return s.addAngleInequality(p1, p2, s.units.toAngle(v), MaxAngle)
+}
+
+// IsActive returns whether a minimum or maximum constraint is at its limit after the last solve
+func (c *Constraint) IsActive() bool {
+	return c.active
+}
+
+// inequalityElement returns the internal element an inequality is measured against
+func inequalityElement(e *Element) (el.SketchElement, bool) {
+	switch e.elementType {
+	case Point, Line, Axis:
+		return e.element, true
+	case Circle, Arc:
+		return e.Center().element, true
+	}
+	return nil, false
+}
+
+func (s *Sketch) addDistanceInequality(p1 *Element, p2 *Element, v float64, ctype ConstraintType) (*Constraint, error) {
+	if v < 0 {
+		return nil, errors.New("distance limit must not be negative")
+	}
+	c := InequalityConstraint(p1, p2, ctype)
+	c.dataValue = v
+
+	var e1, e2 el.SketchElement
+	if p2 == nil {
+		if p1.elementType != Line {
+			return nil, errors.New("incorrect element types for distance limit constraint")
+		}
+		e1, e2 = p1.Start().element, p1.End().element
+	} else {
+		var ok1, ok2 bool
+		e1, ok1 = inequalityElement(p1)
+		e2, ok2 = inequalityElement(p2)
+		if !ok1 || !ok2 || e1.GetID() == e2.GetID() {
+			return nil, errors.New("incorrect element types for distance limit constraint")
+		}
+	}
+
+	bound := ic.Minimum
+	if ctype == MaxDistance {
+		bound = ic.Maximum
+	}
+	var value big.Float
+	value.SetPrec(utils.FloatPrecision).SetFloat64(v)
+	c.inequality = s.sketch.AddInequality(ic.Distance, bound, e1, e2, &value)
+	s.addInequality(c)
+
+	return c, nil
+}
+
+func (s *Sketch) addAngleInequality(p1 *Element, p2 *Element, v float64, ctype ConstraintType) (*Constraint, error) {
+	if (p1.elementType != Line && p1.elementType != Axis) || (p2.elementType != Line && p2.elementType != Axis) {
+		return nil, errors.New("incorrect element types for angle limit constraint")
+	}
+	if v < 0 || v > 180 {
+		return nil, errors.New("angle limit must be between 0 and 180 degrees")
+	}
+	c := InequalityConstraint(p1, p2, ctype)
+	c.dataValue = v
+
+	bound := ic.Minimum
+	if ctype == MaxAngle {
+		bound = ic.Maximum
+	}
+	var value big.Float
+	value.SetPrec(utils.FloatPrecision).SetFloat64(v * math.Pi / 180)
+	c.inequality = s.sketch.AddInequality(ic.Angle, bound, p1.element, p2.element, &value)
+	s.addInequality(c)
+
+	return c, nil
+}
+
+func (s *Sketch) addInequality(c *Constraint) {
+	s.constraints = append(s.constraints, c)
+	for _, e := range c.elements {
+		s.eToC[e.id] = append(s.eToC[e.id], c)
+	}
+}
+
+func (c *Constraint) isInequality() bool {
+	return c.inequality != nil
+}
+
+// solveInequalities enforces inequality, area and perimeter constraints after the graph solve and records
+// which inequalities are active
+func (s *Sketch) solveInequalities() solver.SolveState {
+	hasInequalities := false
+	for _, c := range s.constraints {
+		hasInequalities = hasInequalities || c.isInequality() || c.isLoop() || len(c.sides) > 0
+	}
+	if !hasInequalities {
+		return solver.Solved
+	}
+
+	state := s.sketch.SolveInequalities()
+	for _, c := range s.constraints {
+		if !c.isInequality() && !c.isLoop() {
+			continue
+		}
+		if c.isInequality() {
+			c.active = s.sketch.IsInequalityActive(c.inequality)
+		}
+		c.state = Resolved
+		if state == solver.Solved {
+			c.state = Solved
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,115 @@
+package dlineate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// AddIntersectionPoint adds a point where the elements e1 and e2 intersect. Each element may be a line,
+// axis, circle or arc. Lines are treated as infinite and arcs as their full circle, so the point may be a
+// virtual intersection such as the sharp corner of a fillet.
+// Where the elements intersect twice, branch selects the intersection:
+//   - for a line and a circle or arc, branch 0 is the intersection nearer the line's start and 1 the other
+//   - for two circles or arcs, branch 0 is to the left and 1 to the right looking from the center of e1
+//     toward the center of e2
+//
+// Lines intersect only once, so branch must be 0.
+// The point is constrained coincident with both elements, so it follows the intersection when the sketch
+// is solved, and may be used in other constraints like any other point. A side constraint keeps it on the
+// chosen branch as the elements move.
+// It returns the point element created.
+func (s *Sketch) AddIntersectionPoint(e1 *Element, e2 *Element, branch int) (*Element, error) {
+	if !isIntersectable(e1) || !isIntersectable(e2) || e1 == e2 {
+		return nil, errors.New("incorrect element types for intersection")
+	}
+	c1, c2 := newProfileCurve(e1), newProfileCurve(e2)
+	for _, c := range []profileCurve{c1, c2} {
+		if c.line && c.length() <= utils.StandardCompare {
+			return nil, errors.New("intersecting lines must have a length")
+		}
+	}
+
+	var points [][2]float64
+	switch {
+	case c1.line && c2.line:
+		cross := ((c1.x2 - c1.x1) * (c2.y2 - c2.y1)) - ((c1.y2 - c1.y1) * (c2.x2 - c2.x1))
+		if math.Abs(cross) <= utils.StandardCompare*c1.length()*c2.length() {
+			return nil, errors.New("parallel lines do not intersect")
+		}
+		points = lineIntersections(c1, c2)
+	case c1.line:
+		points = circleLineIntersections(c2, c1)
+	case c2.line:
+		points = circleLineIntersections(c1, c2)
+	default:
+		if math.Hypot(c2.cx-c1.cx, c2.cy-c1.cy) <= utils.StandardCompare {
+			return nil, errors.New("concentric circles do not intersect")
+		}
+		points = circleIntersections(c1, c2)
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("elements %d and %d do not intersect", e1.id, e2.id)
+	}
+	if branch < 0 || branch >= len(points) {
+		return nil, fmt.Errorf("intersection branch must be between 0 and %d", len(points)-1)
+	}
+
+	x, y := points[branch][0], points[branch][1]
+	p := s.addPoint(x, y)
+	s.AddCoincidentConstraint(p, e1)
+	s.AddCoincidentConstraint(p, e2)
+	if len(points) > 1 {
+		s.addBranchConstraint(p, e1, e2, c1, c2, branch)
+	}
+
+	utils.Logger.Info().
+		Uint("point", p.element.GetID()).
+		Uint("element 1", e1.element.GetID()).
+		Uint("element 2", e2.element.GetID()).
+		Int("branch", branch).
+		Float64("x", x).
+		Float64("y", y).
+		Msg("Added intersection point")
+	return p, nil
+}
+
+// addBranchConstraint keeps an intersection point p of e1 and e2 on the given branch. The point is kept on
+// the same side of the line between the centers of two circles, or of the perpendicular to a line through
+// the center of a circle.
+func (s *Sketch) addBranchConstraint(p *Element, e1 *Element, e2 *Element, c1 profileCurve, c2 profileCurve, branch int) {
+	measure, side := numeric.LoopOrientation, 1.0
+	var points []el.SketchElement
+	switch {
+	case c1.line:
+		// Branch 0 is nearer the line's start, behind the circle's center
+		measure, side = numeric.LoopProjection, -1
+		points = []el.SketchElement{e1.element, e2.Center().element, p.element}
+	case c2.line:
+		measure, side = numeric.LoopProjection, -1
+		points = []el.SketchElement{e2.element, e1.Center().element, p.element}
+	default:
+		// Branch 0 is to the left of the centers, a counter-clockwise turn
+		points = []el.SketchElement{e1.Center().element, e2.Center().element, p.element}
+	}
+	if branch == 1 {
+		side = -side
+	}
+	s.addSideConstraint([]*Element{p, e1, e2}, measure, points, side)
+}
+
+// isIntersectable returns whether an element can be used to find an intersection point
+func isIntersectable(e *Element) bool {
+	if e == nil {
+		return false
+	}
+	switch e.elementType {
+	case Line, Axis, Circle, Arc:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// Mirror copies points, lines, circles and arcs reflected across axis, which may be a line or axis.
+// Arcs are reversed so the copy runs clockwise like the original. Each copied point is constrained
+// symmetric with its original across axis, and each circle has a radius equal to its original, so the
+// copies stay mirrored when the originals or axis change and the sketch is solved. Points shared by the
+// elements are shared by the copies, and points on axis are shared with their copies.
+// It returns the copies in the order of elements, or an error if an element or axis isn't supported.
+func (s *Sketch) Mirror(elements []*Element, axis *Element) ([]*Element, error) {
+	if len(elements) == 0 {
+		return nil, errors.New("mirror requires elements")
+	}
+	if axis == nil || (axis.elementType != Line && axis.elementType != Axis) {
+		return nil, errors.New("mirror axis must be a line or axis")
+	}
+	curve := newProfileCurve(axis)
+	ux, uy, length := unitVector(curve.x2-curve.x1, curve.y2-curve.y1)
+	if utils.StandardFloatCompare(length, 0) == 0 {
+		return nil, errors.New("mirror axis must have a length")
+	}
+	for _, e := range elements {
+		if e == nil {
+			return nil, errors.New("incorrect element types for mirror")
+		}
+		if e == axis {
+			return nil, errors.New("the mirror axis can't be mirrored")
+		}
+		switch e.elementType {
+		case Point, Line, Circle, Arc:
+		default:
+			return nil, errors.New("incorrect element types for mirror")
+		}
+	}
+
+	reflect := func(x float64, y float64) (float64, float64) {
+		t := ((x - curve.x1) * ux) + ((y - curve.y1) * uy)
+		fx, fy := curve.x1+(t*ux), curve.y1+(t*uy)
+		return (2 * fx) - x, (2 * fy) - y
+	}
+
+	copies := make([]*Element, 0, len(elements))
+	// The copy of each original point
+	points := make(map[uint]*Element)
+	for _, e := range elements {
+		var c *Element
+		if e.elementType == Arc {
+			// Reflecting reverses an arc, so its reflected end is the copy's start
+			v := e.values
+			cx, cy := reflect(v[0], v[1])
+			sx, sy := reflect(v[4], v[5])
+			ex, ey := reflect(v[2], v[3])
+			c = s.addArc(cx, cy, sx, sy, ex, ey)
+			c.SetConstruction(e.construction)
+			s.clearArcConstraints(c)
+		} else {
+			c = s.copyElement(e, reflect)
+		}
+		copies = append(copies, c)
+
+		originals, copyPoints := patternPoints(e), patternPoints(c)
+		if e.elementType == Arc {
+			originals = []*Element{e.children[0], e.children[2], e.children[1]}
+		}
+		for i, original := range originals {
+			id := original.element.GetID()
+			if p, ok := points[id]; ok {
+				s.AddCoincidentConstraint(p, copyPoints[i])
+				continue
+			}
+			x, y := reflect(original.values[0], original.values[1])
+			if utils.StandardFloatCompare(math.Hypot(x-original.values[0], y-original.values[1]), 0) == 0 {
+				s.AddCoincidentConstraint(original, copyPoints[i])
+				points[id] = original
+				continue
+			}
+			points[id] = copyPoints[i]
+			if _, err := s.AddSymmetricConstraint(original, copyPoints[i], axis); err != nil {
+				return nil, err
+			}
+		}
+		if e.elementType == Circle {
+			s.AddEqualConstraint(e, c)
+		}
+	}
+
+	utils.Logger.Info().
+		Int("elements", len(elements)).
+		Uint("axis", axis.element.GetID()).
+		Msg("Added mirror")
+	return copies, nil
+}
@@ -6,8 +6,17 @@ import (
 	"github.com/marcuswu/dlineate/utils"
 )
 
+// AddParallelConstraint adds a constraint keeping the lines p1 and p2 parallel.
+// The lines keep their current direction, so lines currently pointing in opposite directions
+// are constrained to be 180 degrees apart rather than reversing one of them.
 func (s *Sketch) AddParallelConstraint(p1 *Element, p2 *Element) (*Constraint, error) {
-	c, e := s.AddAngleConstraint(p1, p2, 0, false)
+	angle := 0.0
+	if x1, y1, ok := p1.direction(); ok {
+		if x2, y2, ok := p2.direction(); ok && (x1*x2)+(y1*y2) < 0 {
+			angle = 180
+		}
+	}
+	c, e := s.addAngle(p1, p2, angle, false)
 	if e != nil {
 		utils.Logger.Error().Msgf("error: %s", e)
 	}
@@ -17,16 +26,18 @@ func (s *Sketch) AddParallelConstraint(p1 *Element, p2 *Element) (*Constraint, e
 	return c, e
 }
 
+// AddHorizontalConstraint adds a constraint keeping the line p1 parallel to the X axis.
+// A line drawn with its start right of its end keeps that direction.
 func (s *Sketch) AddHorizontalConstraint(p1 *Element) (*Constraint, error) {
-	// Check p1's points to see if start is right of end; if so, angle shold be 180 degrees
 	if p1.elementType != Line && p1.elementType != Axis {
-		return nil, errors.New("incorrect element types for vertical constraint")
+		return nil, errors.New("incorrect element types for horizontal constraint")
 	}
 	return s.AddParallelConstraint(p1, s.XAxis)
 }
 
+// AddVerticalConstraint adds a constraint keeping the line p1 parallel to the Y axis.
+// A line drawn with its start above its end keeps that direction.
 func (s *Sketch) AddVerticalConstraint(p1 *Element) (*Constraint, error) {
-	// Check p1's points to see if start is below end; if so, angle shold be 180 degrees
 	if p1.elementType != Line && p1.elementType != Axis {
 		return nil, errors.New("incorrect element types for vertical constraint")
 	}
@@ -0,0 +1,273 @@
+package dlineate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	ic "github.com/marcuswu/dlineate/internal/constraint"
+	"github.com/marcuswu/dlineate/internal/expression"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// SetParameter sets the named parameter to the value v.
+// Parameters are used by name in parameter expressions and in constraint expressions. Constraints using
+// expressions are updated with the new value, and if the sketch has already been solved it is solved again.
+func (s *Sketch) SetParameter(name string, v float64) error {
+	return s.setParameter(name, expression.Number(v))
+}
+
+// SetParameterExpression sets the named parameter to an expression of numbers and other parameters,
+// for example "2 * height + 3". Expressions support +, -, *, /, ^, parentheses and the functions
+// sqrt, abs, sin, cos and tan, which take angles in the sketch's angle unit.
+// It returns an error if the expression can't be parsed or if parameters would depend on themselves.
+func (s *Sketch) SetParameterExpression(name string, expr string) error {
+	e, err := expression.Parse(expr)
+	if err != nil {
+		return err
+	}
+	return s.setParameter(name, e)
+}
+
+// Parameter returns the current value of the named parameter
+func (s *Sketch) Parameter(name string) (float64, error) {
+	return s.evaluateParameter(name, nil)
+}
+
+// AddDistanceExpression adds a distance constraint like AddDistanceConstraint with its value from an
+// expression of parameters such as "width / 2".
+func (s *Sketch) AddDistanceExpression(p1 *Element, p2 *Element, expr string) (*Constraint, error) {
+	e, v, err := s.evaluateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	c := s.AddDistanceConstraint(p1, p2, v)
+	c.expression = e
+	return c, nil
+}
+
+// AddAngleExpression adds an angle constraint like AddAngleConstraint with its value from an expression
+// of parameters.
+func (s *Sketch) AddAngleExpression(p1 *Element, p2 *Element, expr string, useSupplementary bool) (*Constraint, error) {
+	e, v, err := s.evaluateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.AddAngleConstraint(p1, p2, v, useSupplementary)
+	if err != nil {
+		return nil, err
+	}
+	c.expression = e
+	return c, nil
+}
+
+// AddRatioExpression adds a ratio constraint like AddRatioConstraint with its value from an expression
+// of parameters.
+func (s *Sketch) AddRatioExpression(p1 *Element, p2 *Element, expr string) (*Constraint, error) {
+	e, v, err := s.evaluateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	c := s.AddRatioConstraint(p1, p2, v)
+	if c == nil {
+		return nil, errors.New("incorrect element types for ratio constraint")
+	}
+	c.expression = e
+	return c, nil
+}
+
+// Expression returns the expression a constraint's value comes from or an empty string if it has none
+func (c *Constraint) Expression() string {
+	if c.expression == nil {
+		return ""
+	}
+	return c.expression.String()
+}
+
+func (s *Sketch) setParameter(name string, e *expression.Expression) error {
+	if !expression.IsName(name) {
+		return fmt.Errorf("invalid parameter name %q", name)
+	}
+	previous, existed := s.parameters[name]
+	restore := func() {
+		if existed {
+			s.parameters[name] = previous
+		} else {
+			delete(s.parameters, name)
+		}
+	}
+	s.parameters[name] = e
+	if err := s.parameterCycle(name, nil); err != nil {
+		restore()
+		return err
+	}
+
+	if err := s.updateExpressions(); err != nil {
+		// Put constraints back on the values from the previous parameter
+		restore()
+		s.updateExpressions()
+		return err
+	}
+	return nil
+}
+
+// parameterCycle returns an error if the named parameter depends on itself
+func (s *Sketch) parameterCycle(name string, path []string) error {
+	for i, p := range path {
+		if p == name {
+			cycle := append(append([]string{}, path[i:]...), name)
+			return fmt.Errorf("parameter cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	e, ok := s.parameters[name]
+	if !ok {
+		return nil
+	}
+	path = append(path, name)
+	for _, v := range e.Variables() {
+		if err := s.parameterCycle(v, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sketch) evaluateParameter(name string, path []string) (float64, error) {
+	if err := s.parameterCycle(name, path); err != nil {
+		return 0, err
+	}
+	e, ok := s.parameters[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown parameter %q", name)
+	}
+	path = append(append([]string{}, path...), name)
+	return s.evaluate(e, func(v string) (float64, error) {
+		return s.evaluateParameter(v, path)
+	})
+}
+
+// evaluate evaluates an expression with trigonometric functions taking the sketch's angle unit
+func (s *Sketch) evaluate(e *expression.Expression, lookup expression.Lookup) (float64, error) {
+	return e.EvaluateAngles(lookup, s.units.toAngle(1)*math.Pi/180)
+}
+
+// evaluateExpression parses an expression and evaluates it with the sketch's parameters
+func (s *Sketch) evaluateExpression(expr string) (*expression.Expression, float64, error) {
+	e, err := expression.Parse(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+	v, err := s.evaluate(e, s.Parameter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return e, v, nil
+}
+
+// updateExpressions re-evaluates constraint expressions after a parameter change. Constraints whose
+// internal constraints were derived from other values are resolved again. If the sketch has been solved,
+// it is solved again with the new values.
+func (s *Sketch) updateExpressions() error {
+	values := make(map[*Constraint]float64)
+	for _, c := range s.constraints {
+		if c.expression == nil {
+			continue
+		}
+		v, err := s.evaluate(c.expression, s.Parameter)
+		if err != nil {
+			return err
+		}
+		values[c] = v
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	for c, v := range values {
+		s.setConstraintValue(c, s.units.toCanonical(c.constraintType, v))
+	}
+	s.unresolveDerivedConstraints()
+
+	if s.passes == 0 {
+		return nil
+	}
+	return s.Solve()
+}
+
+// setConstraintValue updates the value of a constraint and its internal constraints
+func (s *Sketch) setConstraintValue(c *Constraint, v float64) {
+	c.dataValue = v
+	switch c.constraintType {
+	case Distance:
+		if c.isDerived() {
+			return
+		}
+		for _, constraint := range c.constraints {
+			constraint.Value.SetPrec(utils.FloatPrecision).SetFloat64(v)
+		}
+	case Angle:
+		radians := angleRadians(v, c.supplementary)
+		c.dataValue, _ = radians.Float64()
+		c.dataValue *= 180 / math.Pi
+		for _, constraint := range c.constraints {
+			constraint.Value.Set(radians)
+		}
+	}
+}
+
+// isDerived returns whether a constraint's internal constraints are calculated from other constraints
+// or solved elements
+func (c *Constraint) isDerived() bool {
+	switch c.constraintType {
+	case Ratio, Midpoint, HorizontalDistance, VerticalDistance, ArcAngle, ArcLength, Tangent, Symmetric, Concentric, ThroughPoints, PolygonCenter:
+		return true
+	case Distance, Coincident:
+		// Distances to circles and arcs depend on the radius
+		for _, e := range c.elements {
+			if len(c.elements) > 1 && (e.elementType == Circle || e.elementType == Arc) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unresolveDerivedConstraints removes the internal constraints of derived constraints so they are
+// resolved again from current values during the next solve
+func (s *Sketch) unresolveDerivedConstraints() {
+	// Clear solved state so derived values aren't calculated from the previous solve
+	s.sketch.ResetClusters()
+	for _, c := range s.constraints {
+		if c.state == Unresolved || !c.isDerived() {
+			continue
+		}
+		s.unresolveConstraint(c)
+	}
+}
+
+// unresolveConstraint removes the internal constraints of a derived constraint so it is resolved again
+func (s *Sketch) unresolveConstraint(c *Constraint) {
+	for _, constraint := range c.constraints {
+		s.sketch.RemoveConstraint(constraint)
+		for _, e := range c.elements {
+			e.forgetConstraint(constraint)
+		}
+	}
+	c.constraints = make([]*ic.Constraint, 0)
+	s.removeDerivedSides(c)
+	c.err = nil
+	c.state = Unresolved
+}
+
+// forgetConstraint removes an internal constraint from an element and its children
+func (e *Element) forgetConstraint(constraint *ic.Constraint) {
+	for i := len(e.constraints) - 1; i >= 0; i-- {
+		if e.constraints[i] == constraint {
+			e.constraints = append(e.constraints[:i], e.constraints[i+1:]...)
+		}
+	}
+	for _, child := range e.children {
+		child.forgetConstraint(constraint)
+	}
+}
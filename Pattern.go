@@ -0,0 +1,251 @@
+package dlineate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/marcuswu/dlineate/internal/expression"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// LinearPattern copies the elements along direction, a line or axis, count times including the originals.
+// Each copy is placed spacing from the one before it in the direction of the line from its start to its
+// end or the positive direction of an axis.
+// The elements are the seed of the pattern. Each copied point is constrained the spacing from the same
+// point in the previous copy on a line parallel to direction, and each circle has a radius equal to its
+// seed, so changes to the seed or the spacing update every copy when the sketch is solved. Points shared
+// by the elements are shared by the copies.
+// It returns the copies of the elements for each instance after the seed, in the order of elements.
+func (s *Sketch) LinearPattern(elements []*Element, count int, direction *Element, spacing float64) ([][]*Element, error) {
+	return s.linearPattern(elements, count, direction, s.units.toLength(spacing), nil)
+}
+
+// LinearPatternExpression adds a linear pattern like LinearPattern with its spacing from an expression
+// of parameters.
+func (s *Sketch) LinearPatternExpression(elements []*Element, count int, direction *Element, expr string) ([][]*Element, error) {
+	e, v, err := s.evaluateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return s.linearPattern(elements, count, direction, s.units.toLength(v), e)
+}
+
+// CircularPattern copies the elements around the point center count times including the originals.
+// Each copy is rotated angle from the one before it, counter-clockwise for positive angles.
+// The elements are the seed of the pattern. Each copied point is constrained to the rotation of the same
+// point in the previous copy by angles between hidden helper lines, and each circle has a radius equal to
+// its seed, so changes to the seed or the angle update every copy when the sketch is solved.
+// Points shared by the elements are shared by the copies.
+// It returns the copies of the elements for each instance after the seed, in the order of elements.
+func (s *Sketch) CircularPattern(elements []*Element, count int, center *Element, angle float64) ([][]*Element, error) {
+	return s.circularPattern(elements, count, center, s.units.toAngle(angle), nil)
+}
+
+// CircularPatternExpression adds a circular pattern like CircularPattern with its angle from an
+// expression of parameters.
+func (s *Sketch) CircularPatternExpression(elements []*Element, count int, center *Element, expr string) ([][]*Element, error) {
+	e, v, err := s.evaluateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return s.circularPattern(elements, count, center, s.units.toAngle(v), e)
+}
+
+// patternStep constrains point p of a copy to the same point prev of the previous instance
+type patternStep func(prev *Element, p *Element) error
+
+func (s *Sketch) linearPattern(elements []*Element, count int, direction *Element, spacing float64, e *expression.Expression) ([][]*Element, error) {
+	if direction == nil || (direction.elementType != Line && direction.elementType != Axis) {
+		return nil, errors.New("pattern direction must be a line or axis")
+	}
+	curve := newProfileCurve(direction)
+	ux, uy, length := unitVector(curve.x2-curve.x1, curve.y2-curve.y1)
+	if utils.StandardFloatCompare(length, 0) == 0 {
+		return nil, errors.New("pattern direction must have a length")
+	}
+	if spacing <= 0 {
+		return nil, errors.New("pattern spacing must be greater than 0")
+	}
+
+	move := func(x float64, y float64, i int) (float64, float64) {
+		return x + (ux * spacing * float64(i)), y + (uy * spacing * float64(i))
+	}
+	step := func(prev *Element, p *Element) error {
+		if _, err := s.AddParallelConstraint(s.addHelperLine(prev, p), direction); err != nil {
+			return err
+		}
+		c := s.addDistance(prev, p, spacing)
+		c.expression = e
+		return nil
+	}
+	return s.pattern(elements, count, nil, move, step)
+}
+
+func (s *Sketch) circularPattern(elements []*Element, count int, center *Element, angle float64, e *expression.Expression) ([][]*Element, error) {
+	if center == nil || center.elementType != Point {
+		return nil, errors.New("pattern center must be a point")
+	}
+	if utils.StandardFloatCompare(math.Mod(angle, 360), 0) == 0 {
+		return nil, errors.New("pattern angle must rotate the elements")
+	}
+	cx, cy := center.values[0], center.values[1]
+	for _, e := range elements {
+		if e == nil || e.elementType == Axis {
+			continue
+		}
+		// Points at the center have no direction to rotate in unless they are the center
+		for _, p := range patternPoints(e) {
+			if p.element.GetID() != center.element.GetID() && utils.StandardFloatCompare(math.Hypot(p.values[0]-cx, p.values[1]-cy), 0) == 0 {
+				return nil, errors.New("pattern points at the center must be coincident with it")
+			}
+		}
+	}
+
+	// The chord from a point to its rotated copy is at 90 degrees plus half the rotation from the line from
+	// the center to the point. With the rotation itself, this places the copy without depending on its
+	// distance from the center.
+	chord := 90 + (angle / 2)
+	var chordExpression *expression.Expression
+	if e != nil {
+		var err error
+		chordExpression, err = expression.Parse(fmt.Sprintf("%v + (%s) / 2", s.units.fromAngle(90), e.String()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	move := func(x float64, y float64, i int) (float64, float64) {
+		sin, cos := math.Sincos(angle * float64(i) * math.Pi / 180)
+		dx, dy := x-cx, y-cy
+		return cx + (dx * cos) - (dy * sin), cy + (dx * sin) + (dy * cos)
+	}
+	// Lines from the center to each point of each instance
+	spokes := make(map[uint]*Element)
+	spoke := func(p *Element) *Element {
+		l, ok := spokes[p.element.GetID()]
+		if !ok {
+			l = s.addHelperLine(center, p)
+			spokes[p.element.GetID()] = l
+		}
+		return l
+	}
+	step := func(prev *Element, p *Element) error {
+		l := spoke(prev)
+		c, err := s.addAngle(l, spoke(p), angle, false)
+		if err != nil {
+			return err
+		}
+		c.expression = e
+		c, err = s.addAngle(l, s.addHelperLine(prev, p), chord, false)
+		if err != nil {
+			return err
+		}
+		c.expression = chordExpression
+		return nil
+	}
+	return s.pattern(elements, count, center, move, step)
+}
+
+// pattern copies the seed elements count - 1 times, placing each copy with move and constraining each of its
+// points to the previous instance with step. Copies of the point fixed, if any, are the point itself.
+func (s *Sketch) pattern(elements []*Element, count int, fixed *Element, move func(float64, float64, int) (float64, float64), step patternStep) ([][]*Element, error) {
+	if len(elements) == 0 {
+		return nil, errors.New("pattern requires elements")
+	}
+	if count < 2 {
+		return nil, errors.New("pattern count must be at least 2")
+	}
+	for _, e := range elements {
+		if e == nil {
+			return nil, errors.New("incorrect element types for pattern")
+		}
+		switch e.elementType {
+		case Point, Line, Circle, Arc:
+		default:
+			return nil, errors.New("incorrect element types for pattern")
+		}
+	}
+
+	instances := make([][]*Element, 0, count-1)
+	// The point in the previous instance for each seed point
+	previous := make(map[uint]*Element)
+	for _, e := range elements {
+		for _, p := range patternPoints(e) {
+			previous[p.element.GetID()] = p
+		}
+	}
+	for i := 1; i < count; i++ {
+		copies := make([]*Element, 0, len(elements))
+		points := make(map[uint]*Element)
+		for _, e := range elements {
+			c := s.copyElement(e, func(x float64, y float64) (float64, float64) { return move(x, y, i) })
+			copies = append(copies, c)
+			if c.elementType == Arc {
+				s.clearArcConstraints(c)
+			}
+			seedPoints, copyPoints := patternPoints(e), patternPoints(c)
+			for j, seed := range seedPoints {
+				id := seed.element.GetID()
+				if fixed != nil && id == fixed.element.GetID() {
+					s.AddCoincidentConstraint(fixed, copyPoints[j])
+					continue
+				}
+				if p, ok := points[id]; ok {
+					s.AddCoincidentConstraint(p, copyPoints[j])
+					continue
+				}
+				points[id] = copyPoints[j]
+				if err := step(previous[id], copyPoints[j]); err != nil {
+					return nil, err
+				}
+			}
+			if e.elementType == Circle {
+				s.AddEqualConstraint(e, c)
+			}
+		}
+		for id, p := range points {
+			previous[id] = p
+		}
+		instances = append(instances, copies)
+	}
+
+	utils.Logger.Info().
+		Int("elements", len(elements)).
+		Int("count", count).
+		Msg("Added pattern")
+	return instances, nil
+}
+
+// patternPoints returns the points which place an element
+func patternPoints(e *Element) []*Element {
+	if e.elementType == Point {
+		return []*Element{e}
+	}
+	return e.children
+}
+
+// copyElement adds a copy of a point, line, circle or arc with its points placed by move
+func (s *Sketch) copyElement(e *Element, move func(float64, float64) (float64, float64)) *Element {
+	v := e.values
+	var c *Element
+	switch e.elementType {
+	case Point:
+		x, y := move(v[0], v[1])
+		c = s.addPoint(x, y)
+	case Line:
+		x1, y1 := move(v[0], v[1])
+		x2, y2 := move(v[2], v[3])
+		c = s.addLine(x1, y1, x2, y2)
+	case Circle:
+		x, y := move(v[0], v[1])
+		c = s.addCircle(x, y, v[2])
+	default:
+		x1, y1 := move(v[0], v[1])
+		x2, y2 := move(v[2], v[3])
+		x3, y3 := move(v[4], v[5])
+		c = s.addArc(x1, y1, x2, y2, x3, y3)
+	}
+	c.SetConstruction(e.construction)
+	return c
+}
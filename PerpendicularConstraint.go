@@ -2,8 +2,17 @@ package dlineate
 
 import "github.com/marcuswu/dlineate/utils"
 
+// AddPerpendicularConstraint adds a constraint keeping the lines p1 and p2 perpendicular.
+// The lines keep their current direction, so the counter-clockwise angle from p1 to p2 is
+// constrained to either 90 or -90 degrees, whichever is closest to the current sketch.
 func (s *Sketch) AddPerpendicularConstraint(p1 *Element, p2 *Element) (*Constraint, error) {
-	c, err := s.AddAngleConstraint(p1, p2, 90, false)
+	angle := 90.0
+	if x1, y1, ok := p1.direction(); ok {
+		if x2, y2, ok := p2.direction(); ok && (x1*y2)-(y1*x2) < 0 {
+			angle = -90
+		}
+	}
+	c, err := s.addAngle(p1, p2, angle, false)
 	if err != nil {
 		utils.Logger.Error().Msgf("error: %s", err)
 	}
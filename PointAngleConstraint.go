@@ -0,0 +1,76 @@
+package dlineate
+
+import (
+	"errors"
+)
+
+func PointAngleConstraint(p1 *Element, vertex *Element, p2 *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	constraint.elements = append(constraint.elements, vertex)
+	constraint.elements = append(constraint.elements, p2)
+	constraint.constraintType = PointAngle
+	constraint.state = Resolved
+
+	return constraint
+}
+
+// AddPointAngleConstraint adds a constraint setting the angle at vertex between the points p1 and p2.
+// The counter-clockwise angle from p1 to p2 around vertex is the positive direction.
+// The angle is measured between hidden helper lines from vertex to each point, which are managed by the sketch.
+func (s *Sketch) AddPointAngleConstraint(p1 *Element, vertex *Element, p2 *Element, v float64) (*Constraint, error) {
+	if p1.elementType != Point || vertex.elementType != Point || p2.elementType != Point {
+		return nil, errors.New("incorrect element types for point angle constraint")
+	}
+	if p1.element.GetID() == vertex.element.GetID() || p2.element.GetID() == vertex.element.GetID() {
+		return nil, errors.New("a point angle constraint requires points distinct from the vertex")
+	}
+
+	l1 := s.addHelperLine(vertex, p1)
+	l2 := s.addHelperLine(vertex, p2)
+	angle, err := s.addAngle(l1, l2, s.units.toAngle(v), false)
+	if err != nil {
+		return nil, err
+	}
+	// The angle is recorded against the points rather than the helper lines
+	s.removeConstraint(angle)
+
+	c := PointAngleConstraint(p1, vertex, p2)
+	c.constraints = append(c.constraints, angle.constraints...)
+	s.constraints = append(s.constraints, c)
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[vertex.id] = append(s.eToC[vertex.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+
+	return c, nil
+}
+
+// addHelperLine adds a hidden line from p1 to p2 with its end points coincident to them
+func (s *Sketch) addHelperLine(p1 *Element, p2 *Element) *Element {
+	l := s.addLine(p1.values[0], p1.values[1], p2.values[0], p2.values[1])
+	l.hidden = true
+	for _, child := range l.children {
+		child.hidden = true
+	}
+	s.AddCoincidentConstraint(p1, l.Start())
+	s.AddCoincidentConstraint(p2, l.End())
+	return l
+}
+
+// removeConstraint removes a constraint from the sketch's bookkeeping leaving its internal constraints in place
+func (s *Sketch) removeConstraint(c *Constraint) {
+	for i, other := range s.constraints {
+		if other == c {
+			s.constraints = append(s.constraints[:i], s.constraints[i+1:]...)
+			break
+		}
+	}
+	for _, e := range c.elements {
+		for i, other := range s.eToC[e.id] {
+			if other == c {
+				s.eToC[e.id] = append(s.eToC[e.id][:i], s.eToC[e.id][i+1:]...)
+				break
+			}
+		}
+	}
+}
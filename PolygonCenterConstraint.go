@@ -0,0 +1,109 @@
+package dlineate
+
+import (
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func PolygonCenterConstraint(center *Element, sides []*Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, center)
+	constraint.elements = append(constraint.elements, sides...)
+	constraint.constraintType = PolygonCenter
+	constraint.state = Unresolved
+
+	return constraint
+}
+
+// addPolygonCenterConstraint adds a constraint keeping center at the center of the regular polygon with
+// the sides provided
+func (s *Sketch) addPolygonCenterConstraint(center *Element, sides []*Element) *Constraint {
+	c := PolygonCenterConstraint(center, sides)
+	s.constraints = append(s.constraints, c)
+	for _, e := range c.elements {
+		s.eToC[e.id] = append(s.eToC[e.id], c)
+	}
+
+	s.resolvePolygonCenterConstraint(c)
+
+	return c
+}
+
+// polygonCornerDistance returns the distance dimensioned from the center of a polygon to one of its corners
+// along with the side starting at that corner
+func (s *Sketch) polygonCornerDistance(center *Element, sides []*Element) (float64, *Element, bool) {
+	for _, c := range s.findConstraints(center) {
+		if c.constraintType != Distance || len(c.elements) != 2 || len(c.constraints) == 0 || c.IsReference() {
+			continue
+		}
+		other := c.elements[0]
+		if other.id == center.id {
+			other = c.elements[1]
+		}
+		for _, side := range sides {
+			if other.id == side.Start().id {
+				v, _ := c.constraints[0].Value.Float64()
+				return v, side, true
+			}
+		}
+	}
+	return 0, nil, false
+}
+
+func (s *Sketch) resolvePolygonCenterConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+
+	/*
+	 * The corners of a regular polygon are on a circle around its center, with each side the chord between
+	 * two of them. Once a side's length or the distance from the center to a corner is known, the other
+	 * follows. When a side is sized, the center is placed at the apothem from the first side's line and the
+	 * radius from its start. When the radius is dimensioned instead, the center is placed at the apothem
+	 * from the line of the side starting at the dimensioned corner, and the first side is sized, which the
+	 * equal constraints between the sides carry to the others.
+	 */
+	center := c.elements[0]
+	sides := c.elements[1:]
+	n := float64(len(sides))
+	chord := 2 * math.Sin(math.Pi/n)
+	side, radius, sized := sides[0], 0.0, false
+	for _, e := range sides {
+		if length, ok := s.resolveLineLength(e); ok {
+			radius, sized = length/chord, true
+			break
+		}
+	}
+	if !sized {
+		r, corner, ok := s.polygonCornerDistance(center, sides)
+		if !ok {
+			return false
+		}
+		side, radius = corner, r
+	}
+	c.dataValue = radius * chord
+
+	apothem := s.addDistanceConstraint(center, side, radius*math.Cos(math.Pi/n))
+	center.constraints = append(center.constraints, apothem)
+	side.constraints = append(side.constraints, apothem)
+	c.constraints = append(c.constraints, apothem)
+	if sized {
+		constraint := s.addDistanceConstraint(center, side.Start(), radius)
+		center.constraints = append(center.constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	} else {
+		constraint := s.addDistanceConstraint(sides[0], nil, c.dataValue)
+		sides[0].constraints = append(sides[0].constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	for _, constraint := range c.constraints {
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("type", c.constraintType.String()).
+			Msg("resolvePolygonCenterConstraint: added constraint")
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
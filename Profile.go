@@ -0,0 +1,491 @@
+package dlineate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// ProfileEdge is a line, arc or circle bounding a profile
+type ProfileEdge struct {
+	Element *Element
+	// Reversed is true when the edge is followed from its end to its start. A reversed circle is followed clockwise.
+	Reversed bool
+}
+
+// Loop is a closed chain of edges where each edge ends where the next one starts
+type Loop struct {
+	Edges            []ProfileEdge
+	CounterClockwise bool
+}
+
+// Profile is a closed region of a sketch bounded by an outer loop with any holes inside it.
+// Outer loops run counter-clockwise and holes run clockwise.
+type Profile struct {
+	Outer Loop
+	Holes []Loop
+}
+
+// Profiles finds the closed regions bounded by the lines, arcs and circles of the sketch. Edges are joined
+// where they share end points, such as points made coincident. Construction and external geometry is ignored.
+// Loops inside other loops are holes of the profile around them and loops inside holes start new profiles.
+// It returns an error if edges don't form closed loops, if more than two edges meet at a point or if
+// edges cross each other.
+func (s *Sketch) Profiles() ([]Profile, error) {
+	edges := make([]*Element, 0)
+	loops := make([]Loop, 0)
+	for _, e := range s.Elements {
+		if !e.isProfileElement() {
+			continue
+		}
+		switch e.elementType {
+		case Line, Arc:
+			edges = append(edges, e)
+		case Circle:
+			loops = append(loops, Loop{Edges: []ProfileEdge{{Element: e}}, CounterClockwise: true})
+		}
+	}
+
+	if err := checkIntersections(s.Elements); err != nil {
+		return nil, err
+	}
+	chains, err := findLoops(edges)
+	if err != nil {
+		return nil, err
+	}
+	loops = append(loops, chains...)
+
+	return nestLoops(loops), nil
+}
+
+// isProfileElement returns whether an element can bound a profile. Construction, external and hidden
+// geometry is ignored.
+func (e *Element) isProfileElement() bool {
+	return !e.isChild && !e.hidden && !e.construction && e.external == nil
+}
+
+// vertexUse is an end of an edge at a vertex
+type vertexUse struct {
+	edge int
+	end  int
+}
+
+// endpointID returns the id of the internal point at the start (0) or end (1) of a line or arc.
+// Points merged by coincident constraints share an id.
+func endpointID(e *Element, end int) uint {
+	if end == 0 {
+		return e.Start().element.GetID()
+	}
+	return e.End().element.GetID()
+}
+
+// findLoops walks lines and arcs by their shared end points into closed loops
+func findLoops(edges []*Element) ([]Loop, error) {
+	vertices := make(map[uint][]vertexUse)
+	order := make([]uint, 0)
+	for i, e := range edges {
+		for end := 0; end < 2; end++ {
+			id := endpointID(e, end)
+			if _, ok := vertices[id]; !ok {
+				order = append(order, id)
+			}
+			vertices[id] = append(vertices[id], vertexUse{edge: i, end: end})
+		}
+	}
+	for _, id := range order {
+		uses := vertices[id]
+		if len(uses) == 1 {
+			return nil, fmt.Errorf("profile is not closed at the end of element %d", edges[uses[0].edge].id)
+		}
+		if len(uses) > 2 {
+			return nil, fmt.Errorf("%d edges meet at a point of element %d", len(uses), edges[uses[0].edge].id)
+		}
+	}
+
+	loops := make([]Loop, 0)
+	visited := make([]bool, len(edges))
+	for first := range edges {
+		if visited[first] {
+			continue
+		}
+		loop := Loop{Edges: make([]ProfileEdge, 0)}
+		start := endpointID(edges[first], 0)
+		use := vertexUse{edge: first, end: 0}
+		for {
+			visited[use.edge] = true
+			e := edges[use.edge]
+			loop.Edges = append(loop.Edges, ProfileEdge{Element: e, Reversed: use.end == 1})
+			exit := endpointID(e, 1-use.end)
+			if exit == start {
+				break
+			}
+			// Continue with the other edge at the exit vertex
+			for _, next := range vertices[exit] {
+				if next.edge != use.edge || next.end != 1-use.end {
+					use = next
+					break
+				}
+			}
+		}
+		loops = append(loops, loop)
+	}
+
+	for i := range loops {
+		loops[i].CounterClockwise = loops[i].signedArea() > 0
+	}
+	return loops, nil
+}
+
+// nestLoops orients loops and groups them into profiles. Loops inside an odd number of other loops are holes.
+func nestLoops(loops []Loop) []Profile {
+	parents := make([][]int, len(loops))
+	for i := range loops {
+		x, y := loops[i].samplePoint()
+		for j := range loops {
+			if i != j && loops[j].contains(x, y) {
+				parents[i] = append(parents[i], j)
+			}
+		}
+	}
+
+	profiles := make([]Profile, 0)
+	profileIndex := make(map[int]int)
+	for i := range loops {
+		if len(parents[i])%2 == 1 {
+			continue
+		}
+		profileIndex[i] = len(profiles)
+		profiles = append(profiles, Profile{Outer: loops[i].oriented(true), Holes: make([]Loop, 0)})
+	}
+	for i := range loops {
+		if len(parents[i])%2 == 0 {
+			continue
+		}
+		// The hole belongs to the innermost loop around it
+		parent := parents[i][0]
+		for _, j := range parents[i] {
+			if len(parents[j]) > len(parents[parent]) {
+				parent = j
+			}
+		}
+		p := &profiles[profileIndex[parent]]
+		p.Holes = append(p.Holes, loops[i].oriented(false))
+	}
+	return profiles
+}
+
+// oriented returns the loop running counter-clockwise or clockwise
+func (l Loop) oriented(counterClockwise bool) Loop {
+	if l.CounterClockwise == counterClockwise {
+		return l
+	}
+	edges := make([]ProfileEdge, len(l.Edges))
+	for i, edge := range l.Edges {
+		edges[len(edges)-1-i] = ProfileEdge{Element: edge.Element, Reversed: !edge.Reversed}
+	}
+	return Loop{Edges: edges, CounterClockwise: counterClockwise}
+}
+
+// points returns the start and end of an edge in the direction it is followed
+func (e ProfileEdge) points() (float64, float64, float64, float64) {
+	v := e.Element.values
+	x1, y1, x2, y2 := v[0], v[1], v[2], v[3]
+	if e.Element.elementType == Arc {
+		x1, y1, x2, y2 = v[2], v[3], v[4], v[5]
+	}
+	if e.Reversed {
+		return x2, y2, x1, y1
+	}
+	return x1, y1, x2, y2
+}
+
+// signedArea returns the area enclosed by a loop, positive when it runs counter-clockwise
+func (l Loop) signedArea() float64 {
+	return l.moments().area
+}
+
+// direction returns 1 when an arc or circle edge is followed counter-clockwise and -1 otherwise.
+// Arcs run clockwise from start to end.
+func (e ProfileEdge) direction() float64 {
+	ccw := e.Reversed
+	if e.Element.elementType == Circle {
+		ccw = !e.Reversed
+	}
+	if ccw {
+		return 1
+	}
+	return -1
+}
+
+// samplePoint returns a point on the loop away from its vertices
+func (l Loop) samplePoint() (float64, float64) {
+	e := l.Edges[0].Element
+	switch e.elementType {
+	case Circle:
+		return e.values[0] + e.values[2], e.values[1]
+	case Arc:
+		return arcMidpoint(e)
+	}
+	return (e.values[0] + e.values[2]) / 2, (e.values[1] + e.values[3]) / 2
+}
+
+// contains returns whether a point is inside a loop using its winding number
+func (l Loop) contains(x float64, y float64) bool {
+	total := 0.0
+	for _, edge := range l.Edges {
+		e := edge.Element
+		if e.elementType == Circle {
+			if math.Hypot(x-e.values[0], y-e.values[1]) < e.values[2] {
+				total += 2 * math.Pi
+			}
+			continue
+		}
+		x1, y1, x2, y2 := edge.points()
+		ax, ay, bx, by := x1-x, y1-y, x2-x, y2-y
+		total += math.Atan2((ax*by)-(ay*bx), (ax*bx)+(ay*by))
+		if e.elementType == Arc && inArcSegment(e, x, y) {
+			// The arc winds around points between it and its chord
+			total += 2 * math.Pi * edge.direction()
+		}
+	}
+	return math.Abs(total) > math.Pi
+}
+
+// arcRadius returns the radius of an arc from its center and start
+func arcRadius(e *Element) float64 {
+	return math.Hypot(e.values[2]-e.values[0], e.values[3]-e.values[1])
+}
+
+// arcMidpoint returns the point halfway along an arc
+func arcMidpoint(e *Element) (float64, float64) {
+	sweep, _ := e.arcSweep()
+	start := math.Atan2(e.values[3]-e.values[1], e.values[2]-e.values[0])
+	r := arcRadius(e)
+	// Arcs run clockwise
+	return e.values[0] + (r * math.Cos(start-(sweep/2))), e.values[1] + (r * math.Sin(start-(sweep/2)))
+}
+
+// inArcSegment returns whether a point is between an arc and its chord
+func inArcSegment(e *Element, x float64, y float64) bool {
+	if math.Hypot(x-e.values[0], y-e.values[1]) >= arcRadius(e) {
+		return false
+	}
+	mx, my := arcMidpoint(e)
+	side := func(px float64, py float64) float64 {
+		return ((e.values[4] - e.values[2]) * (py - e.values[3])) - ((e.values[5] - e.values[3]) * (px - e.values[2]))
+	}
+	return side(x, y)*side(mx, my) > 0
+}
+
+// profileCurve is a line segment, axis, arc or circle used to find where edges cross
+type profileCurve struct {
+	element        *Element
+	line           bool
+	unbounded      bool
+	x1, y1, x2, y2 float64
+	cx, cy, r      float64
+	sweep          float64
+}
+
+func newProfileCurve(e *Element) profileCurve {
+	v := e.values
+	switch e.elementType {
+	case Line:
+		return profileCurve{element: e, line: true, x1: v[0], y1: v[1], x2: v[2], y2: v[3]}
+	case Axis:
+		// The point on the axis nearest the origin and a point one unit along its direction
+		x, y := -v[0]*v[2], -v[1]*v[2]
+		return profileCurve{element: e, line: true, unbounded: true, x1: x, y1: y, x2: x - v[1], y2: y + v[0]}
+	case Arc:
+		sweep, _ := e.arcSweep()
+		return profileCurve{element: e, x1: v[2], y1: v[3], x2: v[4], y2: v[5], cx: v[0], cy: v[1], r: arcRadius(e), sweep: sweep}
+	}
+	return profileCurve{element: e, cx: v[0], cy: v[1], r: v[2], sweep: 2 * math.Pi}
+}
+
+// onCurve returns whether a point on the curve's line or circle is within the curve
+func (c profileCurve) onCurve(x float64, y float64) bool {
+	if c.unbounded || c.element.elementType == Circle {
+		return true
+	}
+	position := c.position(x, y)
+	if c.line {
+		return position >= -utils.StandardCompare && position <= c.length()+utils.StandardCompare
+	}
+	return position <= c.length()+utils.StandardCompare || (2*math.Pi*c.r)-position <= utils.StandardCompare
+}
+
+// position returns the distance along the curve from its start to a point on its line or circle.
+// Points on a line before its start are negative. Arcs are measured clockwise from 0 to the circumference.
+func (c profileCurve) position(x float64, y float64) float64 {
+	if c.line {
+		dx, dy := c.x2-c.x1, c.y2-c.y1
+		return (((x - c.x1) * dx) + ((y - c.y1) * dy)) / math.Hypot(dx, dy)
+	}
+	// Clockwise angle from the arc start to the point
+	sx, sy := c.x1-c.cx, c.y1-c.cy
+	px, py := x-c.cx, y-c.cy
+	angle := -math.Atan2((sx*py)-(sy*px), (sx*px)+(sy*py))
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle * c.r
+}
+
+// length returns the length of a line segment or arc
+func (c profileCurve) length() float64 {
+	if c.line {
+		return math.Hypot(c.x2-c.x1, c.y2-c.y1)
+	}
+	return c.sweep * c.r
+}
+
+// curveEnd is an end point of a curve with the id of its internal point
+type curveEnd struct {
+	x, y float64
+	id   uint
+}
+
+// ends returns the end points of a curve
+func (c profileCurve) ends() []curveEnd {
+	if c.element.elementType == Circle {
+		return nil
+	}
+	return []curveEnd{
+		{x: c.x1, y: c.y1, id: endpointID(c.element, 0)},
+		{x: c.x2, y: c.y2, id: endpointID(c.element, 1)},
+	}
+}
+
+// intersections returns the points where two curves meet
+func (c profileCurve) intersections(o profileCurve) [][2]float64 {
+	points := make([][2]float64, 0)
+	for _, p := range c.crossings(o) {
+		if c.onCurve(p[0], p[1]) && o.onCurve(p[0], p[1]) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// crossings returns the points where the lines and circles the two curves lie on meet
+func (c profileCurve) crossings(o profileCurve) [][2]float64 {
+	switch {
+	case c.line && o.line:
+		return lineIntersections(c, o)
+	case c.line:
+		return circleLineIntersections(o, c)
+	case o.line:
+		return circleLineIntersections(c, o)
+	}
+	return circleIntersections(c, o)
+}
+
+func lineIntersections(c profileCurve, o profileCurve) [][2]float64 {
+	dx1, dy1 := c.x2-c.x1, c.y2-c.y1
+	dx2, dy2 := o.x2-o.x1, o.y2-o.y1
+	cross := (dx1 * dy2) - (dy1 * dx2)
+	length1, length2 := math.Hypot(dx1, dy1), math.Hypot(dx2, dy2)
+	if math.Abs(cross) <= utils.StandardCompare*length1*length2 {
+		// Parallel lines only meet if they are collinear. Overlapping segments meet away from their ends.
+		if math.Abs(((o.x1-c.x1)*dy1)-((o.y1-c.y1)*dx1))/length1 > utils.StandardCompare {
+			return nil
+		}
+		project := func(x float64, y float64) float64 { return (((x - c.x1) * dx1) + ((y - c.y1) * dy1)) / length1 }
+		lo := math.Max(0, math.Min(project(o.x1, o.y1), project(o.x2, o.y2)))
+		hi := math.Min(length1, math.Max(project(o.x1, o.y1), project(o.x2, o.y2)))
+		if hi-lo <= utils.StandardCompare {
+			return [][2]float64{{c.x1 + (dx1 * lo / length1), c.y1 + (dy1 * lo / length1)}}
+		}
+		mid := (lo + hi) / 2
+		return [][2]float64{{c.x1 + (dx1 * mid / length1), c.y1 + (dy1 * mid / length1)}}
+	}
+	t := (((o.x1 - c.x1) * dy2) - ((o.y1 - c.y1) * dx2)) / cross
+	return [][2]float64{{c.x1 + (t * dx1), c.y1 + (t * dy1)}}
+}
+
+func circleLineIntersections(c profileCurve, l profileCurve) [][2]float64 {
+	dx, dy := l.x2-l.x1, l.y2-l.y1
+	length := math.Hypot(dx, dy)
+	ux, uy := dx/length, dy/length
+	// Nearest point on the line to the center
+	t := ((c.cx - l.x1) * ux) + ((c.cy - l.y1) * uy)
+	nx, ny := l.x1+(t*ux), l.y1+(t*uy)
+	d := math.Hypot(c.cx-nx, c.cy-ny)
+	if d > c.r+utils.StandardCompare {
+		return nil
+	}
+	h := math.Sqrt(math.Max(0, (c.r*c.r)-(d*d)))
+	return [][2]float64{{nx - (h * ux), ny - (h * uy)}, {nx + (h * ux), ny + (h * uy)}}
+}
+
+func circleIntersections(c profileCurve, o profileCurve) [][2]float64 {
+	dx, dy := o.cx-c.cx, o.cy-c.cy
+	d := math.Hypot(dx, dy)
+	if d <= utils.StandardCompare {
+		if math.Abs(c.r-o.r) > utils.StandardCompare {
+			return nil
+		}
+		// The same circle. The curves overlap if either has a point within the other away from its ends.
+		points := make([][2]float64, 0)
+		for _, curve := range []profileCurve{c, o} {
+			if curve.element.elementType == Circle {
+				points = append(points, [2]float64{curve.cx + curve.r, curve.cy})
+				continue
+			}
+			mx, my := arcMidpoint(curve.element)
+			points = append(points, [2]float64{mx, my})
+		}
+		return points
+	}
+	if d > c.r+o.r+utils.StandardCompare || d < math.Abs(c.r-o.r)-utils.StandardCompare {
+		return nil
+	}
+	a := ((c.r * c.r) - (o.r * o.r) + (d * d)) / (2 * d)
+	h := math.Sqrt(math.Max(0, (c.r*c.r)-(a*a)))
+	mx, my := c.cx+(a*dx/d), c.cy+(a*dy/d)
+	return [][2]float64{{mx - (h * dy / d), my + (h * dx / d)}, {mx + (h * dy / d), my - (h * dx / d)}}
+}
+
+// checkIntersections returns an error if any profile edges cross or touch away from their shared end points
+func checkIntersections(elements []*Element) error {
+	curves := make([]profileCurve, 0)
+	for _, e := range elements {
+		if !e.isProfileElement() {
+			continue
+		}
+		if e.elementType == Line && utils.StandardFloatCompare(math.Hypot(e.values[2]-e.values[0], e.values[3]-e.values[1]), 0) == 0 {
+			continue
+		}
+		if e.elementType == Line || e.elementType == Arc || e.elementType == Circle {
+			curves = append(curves, newProfileCurve(e))
+		}
+	}
+
+	for i, c := range curves {
+		for _, o := range curves[i+1:] {
+			for _, p := range c.intersections(o) {
+				if !isSharedEnd(c, o, p) {
+					return fmt.Errorf("elements %d and %d intersect at [%f, %f]", c.element.id, o.element.id, p[0], p[1])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isSharedEnd returns whether a point is at an end point shared by two curves
+func isSharedEnd(c profileCurve, o profileCurve, p [2]float64) bool {
+	for _, e1 := range c.ends() {
+		if utils.StandardFloatCompare(math.Hypot(p[0]-e1.x, p[1]-e1.y), 0) != 0 {
+			continue
+		}
+		for _, e2 := range o.ends() {
+			if e1.id == e2.id {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package dlineate
+
+import "math"
+
+// areaMoments holds the integrals over a region of 1, x, y, x^2, y^2 and xy.
+// They are calculated exactly from the boundary with Green's theorem, so counter-clockwise loops
+// are positive and clockwise loops (holes) are negative.
+type areaMoments struct {
+	area float64
+	sx   float64
+	sy   float64
+	sxx  float64
+	syy  float64
+	sxy  float64
+}
+
+func (m *areaMoments) add(o areaMoments) {
+	m.area += o.area
+	m.sx += o.sx
+	m.sy += o.sy
+	m.sxx += o.sxx
+	m.syy += o.syy
+	m.sxy += o.sxy
+}
+
+// Area returns the area enclosed by the loop in the sketch's length unit squared
+func (l Loop) Area() float64 {
+	return math.Abs(l.signedArea()) * math.Pow(l.unitScale(), 2)
+}
+
+// Perimeter returns the length of the loop in the sketch's length unit
+func (l Loop) Perimeter() float64 {
+	perimeter := 0.0
+	for _, edge := range l.Edges {
+		e := edge.Element
+		switch e.elementType {
+		case Line:
+			perimeter += math.Hypot(e.values[2]-e.values[0], e.values[3]-e.values[1])
+		case Arc:
+			sweep, _ := e.arcSweep()
+			perimeter += arcRadius(e) * sweep
+		case Circle:
+			perimeter += 2 * math.Pi * e.values[2]
+		}
+	}
+	return perimeter * l.unitScale()
+}
+
+// Area returns the area of the profile less its holes in the sketch's length unit squared
+func (p Profile) Area() float64 {
+	return math.Abs(p.moments().area) * math.Pow(p.Outer.unitScale(), 2)
+}
+
+// Perimeter returns the total length of the profile's outer loop and holes in the sketch's length unit
+func (p Profile) Perimeter() float64 {
+	perimeter := p.Outer.Perimeter()
+	for _, hole := range p.Holes {
+		perimeter += hole.Perimeter()
+	}
+	return perimeter
+}
+
+// Centroid returns the center of area of the profile
+func (p Profile) Centroid() (float64, float64) {
+	m := p.moments()
+	scale := p.Outer.unitScale()
+	return m.sx / m.area * scale, m.sy / m.area * scale
+}
+
+// SecondMoments returns the second moments of area of the profile about axes through its centroid parallel
+// to the sketch's X and Y axes: Ixx (about the X axis, the integral of y^2), Iyy (the integral of x^2)
+// and the product of inertia Ixy, in the sketch's length unit to the fourth power.
+func (p Profile) SecondMoments() (float64, float64, float64) {
+	m := p.moments()
+	cx, cy := m.sx/m.area, m.sy/m.area
+	// Parallel axis theorem
+	ixx := m.syy - (m.area * cy * cy)
+	iyy := m.sxx - (m.area * cx * cx)
+	ixy := m.sxy - (m.area * cx * cy)
+	scale := math.Pow(p.Outer.unitScale(), 4)
+	return ixx * scale, iyy * scale, ixy * scale
+}
+
+// moments returns the area moments of the profile with its holes removed
+func (p Profile) moments() areaMoments {
+	m := p.Outer.moments()
+	for _, hole := range p.Holes {
+		m.add(hole.moments())
+	}
+	return m
+}
+
+// unitScale returns the factor converting the loop's millimeter values to the sketch's length unit
+func (l Loop) unitScale() float64 {
+	if len(l.Edges) == 0 {
+		return 1
+	}
+	return l.Edges[0].Element.units.fromLength(1)
+}
+
+// moments returns the area moments of the region enclosed by the loop, negative when it runs clockwise
+func (l Loop) moments() areaMoments {
+	var m areaMoments
+	for _, edge := range l.Edges {
+		e := edge.Element
+		switch e.elementType {
+		case Line:
+			x1, y1, x2, y2 := edge.points()
+			m.add(lineMoments(x1, y1, x2, y2))
+		case Arc:
+			x1, y1, _, _ := edge.points()
+			sweep, _ := e.arcSweep()
+			start := math.Atan2(y1-e.values[1], x1-e.values[0])
+			m.add(arcMoments(e.values[0], e.values[1], arcRadius(e), start, start+(sweep*edge.direction())))
+		case Circle:
+			m.add(arcMoments(e.values[0], e.values[1], e.values[2], 0, 2*math.Pi*edge.direction()))
+		}
+	}
+	return m
+}
+
+// lineMoments returns the boundary integrals for a line segment from [x1, y1] to [x2, y2] using the forms
+// A = 1/2 (x dy - y dx), Sx = 1/2 x^2 dy, Sy = -1/2 y^2 dx, Sxx = 1/3 x^3 dy, Syy = -1/3 y^3 dx and Sxy = 1/2 x^2 y dy
+func lineMoments(x1 float64, y1 float64, x2 float64, y2 float64) areaMoments {
+	dx, dy := x2-x1, y2-y1
+	return areaMoments{
+		area: ((x1 * y2) - (x2 * y1)) / 2,
+		sx:   dy * ((x1 * x1) + (x1 * x2) + (x2 * x2)) / 6,
+		sy:   -dx * ((y1 * y1) + (y1 * y2) + (y2 * y2)) / 6,
+		sxx:  dy * ((x1 * x1 * x1) + (x1 * x1 * x2) + (x1 * x2 * x2) + (x2 * x2 * x2)) / 12,
+		syy:  -dx * ((y1 * y1 * y1) + (y1 * y1 * y2) + (y1 * y2 * y2) + (y2 * y2 * y2)) / 12,
+		sxy: dy * ((x1 * x1 * y1) + (x1 * x1 * dy / 2) + (x1 * dx * y1) + (2 * x1 * dx * dy / 3) +
+			(dx * dx * y1 / 3) + (dx * dx * dy / 4)) / 2,
+	}
+}
+
+// arcMoments returns the boundary integrals for the arc of the circle at [cx, cy] with radius r from the
+// angle t0 to t1 in radians, counter-clockwise when t1 > t0, using the same forms as lineMoments
+func arcMoments(cx float64, cy float64, r float64, t0 float64, t1 float64) areaMoments {
+	// Antiderivatives of powers of sine and cosine evaluated from t0 to t1
+	delta := func(f func(t float64) float64) float64 { return f(t1) - f(t0) }
+	sin1 := delta(math.Sin)
+	cos1 := delta(math.Cos)
+	cos2 := delta(func(t float64) float64 { return (t / 2) + (math.Sin(2*t) / 4) })                // cos^2
+	sin2 := delta(func(t float64) float64 { return (t / 2) - (math.Sin(2*t) / 4) })                // sin^2
+	cos3 := delta(func(t float64) float64 { return math.Sin(t) - (math.Pow(math.Sin(t), 3) / 3) }) // cos^3
+	sin3 := delta(func(t float64) float64 { return (math.Pow(math.Cos(t), 3) / 3) - math.Cos(t) }) // sin^3
+	cos4 := delta(func(t float64) float64 { return (3 * t / 8) + (math.Sin(2*t) / 4) + (math.Sin(4*t) / 32) })
+	sin4 := delta(func(t float64) float64 { return (3 * t / 8) - (math.Sin(2*t) / 4) + (math.Sin(4*t) / 32) })
+	sinCos := delta(func(t float64) float64 { return math.Pow(math.Sin(t), 2) / 2 })   // sin cos
+	sinCos2 := delta(func(t float64) float64 { return -math.Pow(math.Cos(t), 3) / 3 }) // sin cos^2
+	sinCos3 := delta(func(t float64) float64 { return -math.Pow(math.Cos(t), 4) / 4 }) // sin cos^3
+
+	// x = cx + r cos t, y = cy + r sin t, dx = -r sin t dt, dy = r cos t dt
+	return areaMoments{
+		area: ((r * r * (t1 - t0)) + (cx * r * sin1) + (cy * r * -cos1)) / 2,
+		sx:   r * ((cx * cx * sin1) + (2 * cx * r * cos2) + (r * r * cos3)) / 2,
+		sy:   r * ((cy * cy * -cos1) + (2 * cy * r * sin2) + (r * r * sin3)) / 2,
+		sxx:  r * ((cx * cx * cx * sin1) + (3 * cx * cx * r * cos2) + (3 * cx * r * r * cos3) + (r * r * r * cos4)) / 3,
+		syy:  r * ((cy * cy * cy * -cos1) + (3 * cy * cy * r * sin2) + (3 * cy * r * r * sin3) + (r * r * r * sin4)) / 3,
+		sxy: r * ((cx * cx * cy * sin1) + (cx * cx * r * sinCos) + (2 * cx * cy * r * cos2) +
+			(2 * cx * r * r * sinCos2) + (r * r * cy * cos3) + (r * r * r * sinCos3)) / 2,
+	}
+}
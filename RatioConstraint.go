@@ -8,6 +8,7 @@ import (
 
 /*
  * Order matters for ratio constraints. p2's magnitude = p1's magnitude * constraint value
+ * The magnitude of a line or arc is its length. The magnitude of a circle is its radius.
  */
 func RatioConstraint(p1 *Element, p2 *Element) *Constraint {
 	constraint := emptyConstraint()
@@ -19,6 +20,9 @@ func RatioConstraint(p1 *Element, p2 *Element) *Constraint {
 	return constraint
 }
 
+// AddRatioConstraint adds a constraint setting the magnitude of p2 to the magnitude of p1 multiplied by v.
+// Lines and arcs are compared by length, measured along the arc, and circles by radius. An arc's length
+// can't be compared to a circle's radius.
 func (s *Sketch) AddRatioConstraint(p1 *Element, p2 *Element, v float64) *Constraint {
 	c := RatioConstraint(p1, p2)
 	c.dataValue = v
@@ -26,6 +30,10 @@ func (s *Sketch) AddRatioConstraint(p1 *Element, p2 *Element, v float64) *Constr
 	if p1.elementType == Point || p2.elementType == Point {
 		return nil
 	}
+	// An arc's length can't be compared to a circle's radius
+	if (p1.elementType == Arc && p2.elementType == Circle) || (p1.elementType == Circle && p2.elementType == Arc) {
+		return nil
+	}
 	s.eToC[p1.id] = append(s.eToC[p1.id], c)
 	s.eToC[p2.id] = append(s.eToC[p2.id], c)
 	s.constraints = append(s.constraints, c)
@@ -39,6 +47,11 @@ func (s *Sketch) resolveRatioConstraint(c *Constraint) bool {
 	p1 := c.elements[0]
 	p2 := c.elements[1]
 
+	// Arcs are compared by arc length
+	if p1.elementType == Arc || p2.elementType == Arc {
+		return s.resolveArcLengthRatio(c)
+	}
+
 	// All line tests
 	dist, ok := s.resolveLineLength(p1)
 	if ok {
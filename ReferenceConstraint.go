@@ -0,0 +1,134 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+)
+
+func ReferenceConstraint(p1 *Element, p2 *Element, ctype ConstraintType) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	if p2 != nil {
+		constraint.elements = append(constraint.elements, p2)
+	}
+	constraint.constraintType = ctype
+	constraint.state = Resolved
+
+	return constraint
+}
+
+// AddReferenceDistance adds a dimension measuring the distance between p1 and p2 without constraining them.
+// If p2 is nil, p1 is measured on its own -- the length of a line or the radius of a circle or arc.
+// Circles and arcs are measured from their center and lines and axes are measured perpendicular to them.
+// The measurement is updated after every solve and is available from the constraint's Value.
+func (s *Sketch) AddReferenceDistance(p1 *Element, p2 *Element) (*Constraint, error) {
+	if p1 == nil || p1 == p2 {
+		return nil, errors.New("incorrect element types for reference distance")
+	}
+	if p2 == nil && p1.elementType != Line && p1.elementType != Circle && p1.elementType != Arc {
+		return nil, errors.New("incorrect element types for reference distance")
+	}
+	if p2 != nil && isLinear(p1) && isLinear(p2) {
+		return nil, errors.New("incorrect element types for reference distance")
+	}
+
+	c := ReferenceConstraint(p1, p2, ReferenceDistance)
+	s.addReference(c)
+	return c, nil
+}
+
+// AddReferenceAngle adds a dimension measuring the counter-clockwise angle in degrees from the line p1 to
+// the line p2 without constraining them.
+// The measurement is updated after every solve and is available from the constraint's Value.
+func (s *Sketch) AddReferenceAngle(p1 *Element, p2 *Element) (*Constraint, error) {
+	if p1 == nil || p2 == nil || !isLinear(p1) || !isLinear(p2) {
+		return nil, errors.New("incorrect element types for reference angle")
+	}
+
+	c := ReferenceConstraint(p1, p2, ReferenceAngle)
+	s.addReference(c)
+	return c, nil
+}
+
+// Value returns the value of a dimension constraint in the sketch's length or angle unit.
+// Reference dimensions return their measurement from the last solve. Parallel and perpendicular constraints
+// return the angle they hold counter-clockwise from the first line to the second, 0 or 180 degrees for parallel
+// lines and 90 or -90 degrees for perpendicular lines. Constraints without a value, such as coincident
+// constraints, return 0.
+func (c *Constraint) Value() float64 {
+	if len(c.elements) == 0 {
+		return c.dataValue
+	}
+	return c.elements[0].units.fromCanonical(c.constraintType, c.dataValue)
+}
+
+// IsReference returns whether a constraint is a reference dimension which measures without constraining
+func (c *Constraint) IsReference() bool {
+	return c.constraintType == ReferenceDistance || c.constraintType == ReferenceAngle
+}
+
+func isLinear(e *Element) bool {
+	return e.elementType == Line || e.elementType == Axis
+}
+
+func (s *Sketch) addReference(c *Constraint) {
+	s.constraints = append(s.constraints, c)
+	for _, e := range c.elements {
+		s.eToC[e.id] = append(s.eToC[e.id], c)
+	}
+	c.dataValue = c.measure()
+}
+
+// updateReferences measures reference dimensions from the current element values
+func (s *Sketch) updateReferences() {
+	for _, c := range s.constraints {
+		if !c.IsReference() {
+			continue
+		}
+		c.dataValue = c.measure()
+		c.state = Solved
+	}
+}
+
+// measure returns the current value of a reference dimension
+func (c *Constraint) measure() float64 {
+	p1 := c.elements[0]
+	if c.constraintType == ReferenceAngle {
+		x1, y1, _ := p1.direction()
+		x2, y2, _ := c.elements[1].direction()
+		return math.Atan2((x1*y2)-(y1*x2), (x1*x2)+(y1*y2)) * 180 / math.Pi
+	}
+
+	if len(c.elements) == 1 {
+		switch p1.elementType {
+		case Line:
+			return math.Hypot(p1.values[2]-p1.values[0], p1.values[3]-p1.values[1])
+		case Circle:
+			return p1.values[2]
+		case Arc:
+			return math.Hypot(p1.values[2]-p1.values[0], p1.values[3]-p1.values[1])
+		}
+		return 0
+	}
+
+	p2 := c.elements[1]
+	if isLinear(p1) {
+		p1, p2 = p2, p1
+	}
+	x, y := p1.values[0], p1.values[1]
+	if !isLinear(p2) {
+		return math.Hypot(p2.values[0]-x, p2.values[1]-y)
+	}
+
+	// Perpendicular distance from the point to the line through p2
+	dx, dy, _ := p2.direction()
+	var ox, oy float64
+	if p2.elementType == Line {
+		ox, oy = p2.values[0], p2.values[1]
+	} else {
+		// The axis passes through -c * (a, b) / (a^2 + b^2)
+		scale := -p2.values[2] / ((p2.values[0] * p2.values[0]) + (p2.values[1] * p2.values[1]))
+		ox, oy = scale*p2.values[0], scale*p2.values[1]
+	}
+	return math.Abs(((x-ox)*dy)-((y-oy)*dx)) / math.Hypot(dx, dy)
+}
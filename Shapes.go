@@ -0,0 +1,267 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// AddRectangle adds a rectangle with opposite corners [x1, y1] and [x2, y2].
+// The sides are joined at the corners and kept horizontal and vertical, so the rectangle is placed by one
+// corner and sized by the length of two adjacent sides.
+// It returns the sides in order: from [x1, y1] to [x2, y1], to [x2, y2], to [x1, y2] and back to [x1, y1].
+func (s *Sketch) AddRectangle(x1 float64, y1 float64, x2 float64, y2 float64) ([]*Element, error) {
+	return s.addRectangle(s.units.toLength(x1), s.units.toLength(y1), s.units.toLength(x2), s.units.toLength(y2))
+}
+
+// AddCenterRectangle adds a rectangle centered on [cx, cy] with a corner at [x, y].
+// The rectangle is constrained like AddRectangle, with construction lines between opposite corners which
+// cross at a center point. The center follows the rectangle, which is placed by a corner and sized by the
+// length of two adjacent sides.
+// It returns the sides in the order of AddRectangle starting from the corner opposite [x, y], followed by
+// the two diagonals and the center point.
+func (s *Sketch) AddCenterRectangle(cx float64, cy float64, x float64, y float64) ([]*Element, error) {
+	cx, cy, x, y = s.units.toLength(cx), s.units.toLength(cy), s.units.toLength(x), s.units.toLength(y)
+	sides, err := s.addRectangle((2*cx)-x, (2*cy)-y, x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	center := s.addPoint(cx, cy)
+	center.construction = true
+	d1 := s.addLine((2*cx)-x, (2*cy)-y, x, y)
+	d2 := s.addLine(x, (2*cy)-y, (2*cx)-x, y)
+	d1.SetConstruction(true)
+	d2.SetConstruction(true)
+	s.AddCoincidentConstraint(sides[0].Start(), d1.Start())
+	s.AddCoincidentConstraint(sides[2].Start(), d1.End())
+	s.AddCoincidentConstraint(sides[1].Start(), d2.Start())
+	s.AddCoincidentConstraint(sides[3].Start(), d2.End())
+	s.AddCoincidentConstraint(center, d1)
+	s.AddCoincidentConstraint(center, d2)
+
+	return append(sides, d1, d2, center), nil
+}
+
+func (s *Sketch) addRectangle(x1 float64, y1 float64, x2 float64, y2 float64) ([]*Element, error) {
+	if utils.StandardFloatCompare(x1, x2) == 0 || utils.StandardFloatCompare(y1, y2) == 0 {
+		return nil, errors.New("rectangle must have a width and height")
+	}
+	sides := []*Element{
+		s.addLine(x1, y1, x2, y1),
+		s.addLine(x2, y1, x2, y2),
+		s.addLine(x2, y2, x1, y2),
+		s.addLine(x1, y2, x1, y1),
+	}
+	s.closeLoop(sides)
+	s.AddHorizontalConstraint(sides[0])
+	s.AddHorizontalConstraint(sides[2])
+	s.AddVerticalConstraint(sides[1])
+	s.AddVerticalConstraint(sides[3])
+
+	utils.Logger.Info().
+		Uint("first side", sides[0].element.GetID()).
+		Msg("Added rectangle")
+	return sides, nil
+}
+
+// AddRegularPolygon adds a polygon with n equal sides and angles, its corners on a circle of radius r
+// centered on [cx, cy]. The first side is at the bottom, parallel to the X axis, and the sides run
+// counter-clockwise.
+// The sides are constrained to equal lengths and equal angles with each other, and the center is held by a
+// polygon center constraint. The polygon is placed by a corner and sized by the length of one side, or placed
+// by its center and sized by the distance from the center to a corner. It is rotated with an angle on any
+// side, or on the side starting at the dimensioned corner when it is sized from its center.
+// It returns the sides in order followed by the center point.
+func (s *Sketch) AddRegularPolygon(cx float64, cy float64, r float64, n int) ([]*Element, error) {
+	if n < 3 {
+		return nil, errors.New("polygon must have at least 3 sides")
+	}
+	cx, cy, r = s.units.toLength(cx), s.units.toLength(cy), s.units.toLength(r)
+	if r <= 0 {
+		return nil, errors.New("polygon radius must be greater than 0")
+	}
+
+	step := 360 / float64(n)
+	corner := func(i int) (float64, float64) {
+		sin, cos := math.Sincos((-90 - (step / 2) + (step * float64(i))) * math.Pi / 180)
+		return cx + (r * cos), cy + (r * sin)
+	}
+	sides := make([]*Element, n)
+	for i := range sides {
+		x1, y1 := corner(i)
+		x2, y2 := corner(i + 1)
+		sides[i] = s.addLine(x1, y1, x2, y2)
+	}
+	s.closeLoop(sides)
+	// The center is resolved before the equal constraints between the sides, which can then use the side
+	// length it finds from the radius
+	center := s.addPoint(cx, cy)
+	center.construction = true
+	s.addPolygonCenterConstraint(center, sides)
+
+	// Each side turns the exterior angle from the one before it and is equal to the first. The last
+	// side's angle and length follow from the others.
+	for i := 0; i < n-2; i++ {
+		if _, err := s.addAngle(sides[i], sides[i+1], step, false); err != nil {
+			return nil, err
+		}
+		s.AddEqualConstraint(sides[0], sides[i+1])
+	}
+
+	utils.Logger.Info().
+		Uint("center", center.element.GetID()).
+		Int("sides", n).
+		Msg("Added regular polygon")
+	return append(sides, center), nil
+}
+
+// AddSlot adds a slot around the points c1 and c2: two arcs of radius r centered on the points, joined by
+// two lines tangent to both. The lines are kept parallel to the line between the points and tangent to the
+// first arc, and each line ends opposite a center, so the slot follows the points and is sized by the
+// radius r, which is dimensioned on the last arc returned.
+// It returns the elements in order around the slot: the line from c1 to c2 on the right looking from c1
+// to c2, the arc around c2, the line back to c1 and the arc around c1.
+func (s *Sketch) AddSlot(c1 *Element, c2 *Element, r float64) ([]*Element, error) {
+	if c1 == nil || c2 == nil || c1.elementType != Point || c2.elementType != Point || c1.id == c2.id {
+		return nil, errors.New("slot centers must be two points")
+	}
+	r = s.units.toLength(r)
+	if r <= 0 {
+		return nil, errors.New("slot radius must be greater than 0")
+	}
+	x1, y1 := c1.values[0], c1.values[1]
+	x2, y2 := c2.values[0], c2.values[1]
+	ux, uy, length := unitVector(x2-x1, y2-y1)
+	if utils.StandardFloatCompare(length, 0) == 0 {
+		return nil, errors.New("slot centers must be apart")
+	}
+	// Offset to the left of the direction from c1 to c2
+	nx, ny := -uy*r, ux*r
+
+	// Arcs run clockwise from start to end, around the outside of each center
+	l1 := s.addLine(x1-nx, y1-ny, x2-nx, y2-ny)
+	a2 := s.addArc(x2, y2, x2+nx, y2+ny, x2-nx, y2-ny)
+	l2 := s.addLine(x2+nx, y2+ny, x1+nx, y1+ny)
+	a1 := s.addArc(x1, y1, x1-nx, y1-ny, x1+nx, y1+ny)
+	s.clearArcConstraints(a1)
+	s.clearArcConstraints(a2)
+	s.AddCoincidentConstraint(c1, a1.Center())
+	s.AddCoincidentConstraint(c2, a2.Center())
+	s.AddCoincidentConstraint(l1.End(), a2.End())
+	s.AddCoincidentConstraint(a2.Start(), l2.Start())
+	s.AddCoincidentConstraint(l2.End(), a1.End())
+	s.AddCoincidentConstraint(a1.Start(), l1.Start())
+	s.addDistance(a1, nil, r)
+
+	// The lines are parallel to the line between the centers and tangent to the first arc, and each ends
+	// where a line from a center meets it at a right angle
+	centers := s.addHelperLine(c1, c2)
+	for _, line := range []*Element{l1, l2} {
+		if _, err := s.AddParallelConstraint(centers, line); err != nil {
+			return nil, err
+		}
+		if _, err := s.AddTangentConstraint(line, a1); err != nil {
+			return nil, err
+		}
+	}
+	for _, end := range [][2]*Element{{c1, l1.Start()}, {c2, l1.End()}, {c2, l2.Start()}, {c1, l2.End()}} {
+		if _, err := s.AddPerpendicularConstraint(centers, s.addHelperLine(end[0], end[1])); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.Logger.Info().
+		Uint("center 1", c1.element.GetID()).
+		Uint("center 2", c2.element.GetID()).
+		Float64("radius", r).
+		Msg("Added slot")
+	return []*Element{l1, a2, l2, a1}, nil
+}
+
+// AddArcThroughPoints adds an arc from the point p1 through p2 to p3.
+// The arc is held by a through points constraint like AddCircleThroughPoints, and its ends are coincident
+// with p1 and p3, so the arc follows the points when the sketch is solved.
+// Arcs run clockwise, so the arc starts at p3 and ends at p1 when the points run counter-clockwise.
+// It returns the arc element created.
+func (s *Sketch) AddArcThroughPoints(p1 *Element, p2 *Element, p3 *Element) (*Element, error) {
+	cx, cy, err := circumcenter(p1, p2, p3)
+	if err != nil {
+		return nil, err
+	}
+	start, end := p1, p3
+	if pointsTurn(p1, p2, p3) > 0 {
+		start, end = p3, p1
+	}
+	arc := s.addArc(cx, cy, start.values[0], start.values[1], end.values[0], end.values[1])
+	s.clearArcConstraints(arc)
+	s.addThroughPointsConstraint(arc, p1, p2, p3)
+	s.AddCoincidentConstraint(start, arc.Start())
+	s.AddCoincidentConstraint(end, arc.End())
+
+	utils.Logger.Info().
+		Uint("arc", arc.element.GetID()).
+		Msg("Added arc through points")
+	return arc, nil
+}
+
+// AddCircleThroughPoints adds a circle through the points p1, p2 and p3.
+// The circle is held by a through points constraint, which places its center at the distance of the
+// circle's radius from p1 and p2 once the three points are placed. The circle follows the points when the
+// sketch is solved and its radius is known to other constraints, such as tangents, like a dimensioned circle.
+// It returns the circle element created.
+func (s *Sketch) AddCircleThroughPoints(p1 *Element, p2 *Element, p3 *Element) (*Element, error) {
+	cx, cy, err := circumcenter(p1, p2, p3)
+	if err != nil {
+		return nil, err
+	}
+	circle := s.addCircle(cx, cy, math.Hypot(p1.values[0]-cx, p1.values[1]-cy))
+	s.addThroughPointsConstraint(circle, p1, p2, p3)
+
+	utils.Logger.Info().
+		Uint("circle", circle.element.GetID()).
+		Msg("Added circle through points")
+	return circle, nil
+}
+
+// closeLoop joins each line's end to the start of the next, and the last line's end to the first's start
+func (s *Sketch) closeLoop(lines []*Element) {
+	for i, line := range lines {
+		s.AddCoincidentConstraint(line.End(), lines[(i+1)%len(lines)].Start())
+	}
+}
+
+// circumcenter returns the center of the circle through three points
+func circumcenter(p1 *Element, p2 *Element, p3 *Element) (float64, float64, error) {
+	for _, p := range []*Element{p1, p2, p3} {
+		if p == nil || p.elementType != Point {
+			return 0, 0, errors.New("incorrect element types for points on a circle")
+		}
+	}
+	x, y, ok := circleCenter(p1.values[0], p1.values[1], p2.values[0], p2.values[1], p3.values[0], p3.values[1])
+	if !ok {
+		return 0, 0, errors.New("points on a circle must not be in a line")
+	}
+	return x, y, nil
+}
+
+// circleCenter returns the center of the circle through [ax, ay], [bx, by] and [cx, cy] if they aren't in a line
+func circleCenter(ax float64, ay float64, bx float64, by float64, cx float64, cy float64) (float64, float64, bool) {
+	d := 2 * (((bx - ax) * (cy - by)) - ((by - ay) * (cx - bx)))
+	scale := math.Max(math.Hypot(bx-ax, by-ay), math.Hypot(cx-bx, cy-by))
+	if math.Abs(d) <= utils.StandardCompare*scale*scale {
+		return 0, 0, false
+	}
+	a2, b2, c2 := (ax*ax)+(ay*ay), (bx*bx)+(by*by), (cx*cx)+(cy*cy)
+	x := ((a2 * (by - cy)) + (b2 * (cy - ay)) + (c2 * (ay - by))) / d
+	y := ((a2 * (cx - bx)) + (b2 * (ax - cx)) + (c2 * (bx - ax))) / d
+	return x, y, true
+}
+
+// pointsTurn returns the cross product of the lines from p1 to p2 and p2 to p3, which is positive when
+// the points run counter-clockwise
+func pointsTurn(p1 *Element, p2 *Element, p3 *Element) float64 {
+	return ((p2.values[0] - p1.values[0]) * (p3.values[1] - p2.values[1])) -
+		((p2.values[1] - p1.values[1]) * (p3.values[0] - p2.values[0]))
+}
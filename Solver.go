@@ -13,6 +13,8 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/marcuswu/dlineate/internal/constraint"
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/expression"
 	core "github.com/marcuswu/dlineate/internal/graph"
 	"github.com/marcuswu/dlineate/internal/solver"
 	"github.com/marcuswu/dlineate/utils"
@@ -25,6 +27,8 @@ type Sketch struct {
 	constraints []*Constraint
 	eToC        map[uint][]*Constraint
 	passes      int
+	parameters  map[string]*expression.Expression
+	units       *unitSystem
 	Origin      *Element
 	XAxis       *Element
 	YAxis       *Element
@@ -44,11 +48,14 @@ func NewSketch() *Sketch {
 	s.Elements = make([]*Element, 0)
 	s.constraints = make([]*Constraint, 0)
 	s.eToC = make(map[uint][]*Constraint)
+	s.parameters = make(map[string]*expression.Expression)
+	s.units = &unitSystem{length: Millimeter, angle: Degree}
+	s.plane = defaultWorkplane()
 	// TODO: These need to be in a special cluster that isn't counted towards solving
 	s.Origin = s.addOrigin()
 	s.XAxis = s.addAxis(0, -1, 0)
 	s.YAxis = s.addAxis(1, 0, 0)
-	s.AddAngleConstraint(s.XAxis, s.YAxis, 90, false)
+	s.addAngle(s.XAxis, s.YAxis, 90, false)
 	s.AddCoincidentConstraint(s.Origin, s.XAxis)
 	s.AddCoincidentConstraint(s.Origin, s.YAxis)
 
@@ -57,9 +64,32 @@ func NewSketch() *Sketch {
 	return s
 }
 
-// SetWorkplane sets the origin and axis orientation of the sketch
+// SetWorkplane sets the origin and axis orientation of the sketch in 3D space.
+// A workplane which isn't valid is logged and leaves the workplane unchanged.
+//
+// Deprecated: Use UseWorkplane, which returns an error for workplanes which aren't valid.
 func (s *Sketch) SetWorkplane(plane *Workplane) {
-	s.plane = plane
+	if err := s.UseWorkplane(plane); err != nil {
+		utils.Logger.Error().Err(err).Msg("SetWorkplane: workplane is not valid")
+	}
+}
+
+// UseWorkplane sets the origin and axis orientation of the sketch in 3D space.
+// The axis directions are normalized and the y direction is made perpendicular to the x direction.
+// It returns an error and leaves the workplane unchanged if a direction is missing or has no length or the
+// directions are parallel.
+func (s *Sketch) UseWorkplane(plane *Workplane) error {
+	wp, err := plane.orthonormalize()
+	if err != nil {
+		return err
+	}
+	s.plane = wp
+	return nil
+}
+
+// Workplane returns the sketch's workplane. Sketches are on the XY plane at the 3D origin by default.
+func (s *Sketch) Workplane() *Workplane {
+	return s.plane
 }
 
 func (s *Sketch) findConstraints(e *Element) []*Constraint {
@@ -77,6 +107,13 @@ func (s *Sketch) findConstraint(ctype ConstraintType, e *Element) (*Constraint,
 	return nil, errors.New("no such constraint")
 }
 
+// newElement returns an empty element using the sketch's units
+func (s *Sketch) newElement() *Element {
+	e := emptyElement()
+	e.units = s.units
+	return e
+}
+
 func (s *Sketch) nextElementID() uint {
 	return uint(len(s.Elements))
 }
@@ -84,7 +121,11 @@ func (s *Sketch) nextElementID() uint {
 // AddPoint adds a point to the sketch at [x, y].
 // It returns the point element created.
 func (s *Sketch) AddPoint(x float64, y float64) *Element {
-	p := emptyElement()
+	return s.addPoint(s.units.toLength(x), s.units.toLength(y))
+}
+
+func (s *Sketch) addPoint(x float64, y float64) *Element {
+	p := s.newElement()
 	p.id = s.nextElementID()
 	p.elementType = Point
 	p.values = append(p.values, x)
@@ -96,7 +137,7 @@ func (s *Sketch) AddPoint(x float64, y float64) *Element {
 }
 
 func (s *Sketch) addOrigin() *Element {
-	o := emptyElement()
+	o := s.newElement()
 	o.id = s.nextElementID()
 	o.elementType = Point
 	o.values = append(o.values, 0)
@@ -110,7 +151,7 @@ func (s *Sketch) addOrigin() *Element {
 }
 
 func (s *Sketch) addAxis(a float64, b float64, c float64) *Element {
-	ax := emptyElement()
+	ax := s.newElement()
 	ax.id = s.nextElementID()
 	ax.elementType = Axis
 	ax.values = append(ax.values, a)
@@ -125,7 +166,12 @@ func (s *Sketch) addAxis(a float64, b float64, c float64) *Element {
 // AddLine adds a line to the sketch from [x1, y1] to [x2, y2].
 // It returns the line element created.
 func (s *Sketch) AddLine(x1 float64, y1 float64, x2 float64, y2 float64) *Element {
-	l := emptyElement()
+	u := s.units
+	return s.addLine(u.toLength(x1), u.toLength(y1), u.toLength(x2), u.toLength(y2))
+}
+
+func (s *Sketch) addLine(x1 float64, y1 float64, x2 float64, y2 float64) *Element {
+	l := s.newElement()
 	l.id = s.nextElementID()
 	l.elementType = Line
 
@@ -140,9 +186,9 @@ func (s *Sketch) AddLine(x1 float64, y1 float64, x2 float64, y2 float64) *Elemen
 	le := l.element.AsLine()
 	s.Elements = append(s.Elements, l)
 
-	start := s.AddPoint(l.values[0], l.values[1])
+	start := s.addPoint(l.values[0], l.values[1])
 	start.isChild = true
-	end := s.AddPoint(l.values[2], l.values[3])
+	end := s.addPoint(l.values[2], l.values[3])
 	end.isChild = true
 	le.Start = start.element.AsPoint()
 	le.End = end.element.AsPoint()
@@ -151,8 +197,8 @@ func (s *Sketch) AddLine(x1 float64, y1 float64, x2 float64, y2 float64) *Elemen
 	l.children = append(l.children, end)
 	s.eToC[end.id] = make([]*Constraint, 0)
 	s.eToC[l.id] = make([]*Constraint, 0)
-	s.AddDistanceConstraint(l, start, 0.0)
-	s.AddDistanceConstraint(l, end, 0.0)
+	s.addDistance(l, start, 0.0)
+	s.addDistance(l, end, 0.0)
 	utils.Logger.Info().
 		Uint("line", l.element.GetID()).
 		Uint("start", l.children[0].element.GetID()).
@@ -164,7 +210,11 @@ func (s *Sketch) AddLine(x1 float64, y1 float64, x2 float64, y2 float64) *Elemen
 // AddCircle adds a circle to the sketch at the center point [x, y] with the radius r.
 // It returns the circle element created.
 func (s *Sketch) AddCircle(x float64, y float64, r float64) *Element {
-	c := emptyElement()
+	return s.addCircle(s.units.toLength(x), s.units.toLength(y), s.units.toLength(r))
+}
+
+func (s *Sketch) addCircle(x float64, y float64, r float64) *Element {
+	c := s.newElement()
 	c.id = s.nextElementID()
 	c.elementType = Circle
 	c.values = append(c.values, x)
@@ -173,7 +223,7 @@ func (s *Sketch) AddCircle(x float64, y float64, r float64) *Element {
 
 	s.Elements = append(s.Elements, c)
 
-	center := s.AddPoint(c.values[0], c.values[1])
+	center := s.addPoint(c.values[0], c.values[1])
 	center.isChild = true
 	c.element = center.element
 
@@ -190,7 +240,12 @@ func (s *Sketch) AddCircle(x float64, y float64, r float64) *Element {
 // The arc is created clockwise from start to end point. If the reverse arc is needed, swap start and end.
 // It returns the arc element created.
 func (s *Sketch) AddArc(x1 float64, y1 float64, x2 float64, y2 float64, x3 float64, y3 float64) *Element {
-	a := emptyElement()
+	u := s.units
+	return s.addArc(u.toLength(x1), u.toLength(y1), u.toLength(x2), u.toLength(y2), u.toLength(x3), u.toLength(y3))
+}
+
+func (s *Sketch) addArc(x1 float64, y1 float64, x2 float64, y2 float64, x3 float64, y3 float64) *Element {
+	a := s.newElement()
 	a.id = s.nextElementID()
 	a.elementType = Arc
 	a.values = append(a.values, x1)
@@ -202,23 +257,23 @@ func (s *Sketch) AddArc(x1 float64, y1 float64, x2 float64, y2 float64, x3 float
 
 	s.Elements = append(s.Elements, a)
 
-	center := s.AddPoint(a.values[0], a.values[1])
+	center := s.addPoint(a.values[0], a.values[1])
 	center.isChild = true
 	a.element = center.element
 	a.children = append(a.children, center)
 	s.eToC[center.id] = make([]*Constraint, 0)
 
-	start := s.AddPoint(a.values[2], a.values[3])
+	start := s.addPoint(a.values[2], a.values[3])
 	start.isChild = true
 	s.eToC[start.id] = make([]*Constraint, 0)
-	end := s.AddPoint(a.values[4], a.values[5])
+	end := s.addPoint(a.values[4], a.values[5])
 	end.isChild = true
 	s.eToC[end.id] = make([]*Constraint, 0)
 	s.eToC[a.id] = make([]*Constraint, 0)
 	a.children = append(a.children, start)
 	a.children = append(a.children, end)
-	s.AddDistanceConstraint(a, start, 0)
-	s.AddDistanceConstraint(a, end, 0)
+	s.addDistance(a, start, 0)
+	s.addDistance(a, end, 0)
 	utils.Logger.Info().
 		Uint("arc", a.element.GetID()).
 		Uint("start", a.children[1].element.GetID()).
@@ -227,6 +282,14 @@ func (s *Sketch) AddArc(x1 float64, y1 float64, x2 float64, y2 float64, x3 float
 	return a
 }
 
+// clearArcConstraints deletes the constraints holding an arc's end points on its circle, for arcs whose
+// points are all placed by other constraints. Keeping both would over constrain the arc.
+func (s *Sketch) clearArcConstraints(arc *Element) {
+	for _, c := range s.findConstraints(arc) {
+		s.deleteConstraint(c)
+	}
+}
+
 func (s *Sketch) MakeFixed(e *Element) {
 	s.sketch.MakeFixed(e.element)
 	for _, el := range e.children {
@@ -249,6 +312,28 @@ func (s *Sketch) resolveConstraint(c *Constraint) bool {
 	case Perpendicular:
 		fallthrough
 	case Parallel:
+		fallthrough
+	case Collinear:
+		fallthrough
+	case PointAngle:
+		fallthrough
+	case MinDistance:
+		fallthrough
+	case MaxDistance:
+		fallthrough
+	case MinAngle:
+		fallthrough
+	case MaxAngle:
+		fallthrough
+	case Area:
+		fallthrough
+	case Perimeter:
+		fallthrough
+	case Side:
+		fallthrough
+	case ReferenceDistance:
+		fallthrough
+	case ReferenceAngle:
 		c.state = Resolved
 		return true
 	case Ratio:
@@ -257,6 +342,22 @@ func (s *Sketch) resolveConstraint(c *Constraint) bool {
 		return s.resolveMidpointConstraint(c)
 	case Tangent:
 		return s.resolveTangentConstraint(c)
+	case Concentric:
+		return s.resolveConcentricConstraint(c)
+	case ThroughPoints:
+		return s.resolveThroughPointsConstraint(c)
+	case PolygonCenter:
+		return s.resolvePolygonCenterConstraint(c)
+	case HorizontalDistance:
+		fallthrough
+	case VerticalDistance:
+		return s.resolveAxisDistanceConstraint(c)
+	case ArcAngle:
+		fallthrough
+	case ArcLength:
+		return s.resolveArcConstraint(c)
+	case Symmetric:
+		return s.resolveSymmetricConstraint(c)
 	}
 
 	return c.state == Resolved
@@ -291,6 +392,15 @@ func (s *Sketch) isElementSolved(e *Element) bool {
 	return s.sketch.IsElementSolved(e.element)
 }
 
+// currentPoint returns a point's location in the sketch graph, which may have moved since the element
+// was last loaded from it
+func (s *Sketch) currentPoint(e *Element) *el.SketchPoint {
+	if current, ok := s.sketch.GetElement(e.element.GetID()); ok {
+		return current.AsPoint()
+	}
+	return e.element.AsPoint()
+}
+
 func (s *Sketch) getDistanceConstraint(e *Element) (*Constraint, bool) {
 	if e.elementType != Line {
 		dc, err := s.findConstraint(Distance, e)
@@ -315,6 +425,9 @@ func (s *Sketch) getDistanceConstraint(e *Element) (*Constraint, bool) {
 	// Look for a constraint on a line between the start and end
 	constraints := s.findConstraints(e.children[0])
 	for _, c := range constraints {
+		if c.IsReference() {
+			continue
+		}
 		if c.elements[0] == e.children[1] || c.elements[1] == e.children[1] {
 			// if c.elements[0] == e.children[1] || c.elements[1] == e.children[2] {
 			return c, true
@@ -346,11 +459,17 @@ func (s *Sketch) resolveLineLength(e *Element) (float64, bool) {
 		return v, ok
 	}
 
+	// Sides of regular polygons have the length their center is placed with
+	if pc, err := s.findConstraint(PolygonCenter, e); err == nil {
+		return pc.dataValue, true
+	}
+
 	startConstrained := s.isElementSolved(e.children[0])
 	endConstrained := s.isElementSolved(e.children[1])
 	if startConstrained && endConstrained {
 		// resolve constraint setting p2's distance to the distance from p1 start to p1 end
-		v, _ := e.children[0].element.AsPoint().DistanceTo(e.children[1].element.AsPoint()).Float64()
+		// The end points are measured in the graph, as the elements hold their drawn values until the solve ends
+		v, _ := s.currentPoint(e.children[0]).DistanceTo(s.currentPoint(e.children[1])).Float64()
 
 		return v, true
 	}
@@ -374,6 +493,13 @@ func (s *Sketch) resolveCurveRadius(e *Element) (*big.Float, bool) {
 		return &v, true
 	}
 
+	// Circles through points have the radius their center is placed from the points with
+	if tc, err := s.findConstraint(ThroughPoints, e); err == nil && len(tc.constraints) > 0 {
+		var v big.Float
+		v.Copy(&tc.constraints[0].Value)
+		return &v, true
+	}
+
 	// Circles and Arcs with solved center and solved elements coincident or distance to the circle / arc
 	if centerSolved := s.isElementSolved(e.children[0]); centerSolved {
 		// Find constraints against the curve itself (not against its center or other child elements)
@@ -397,6 +523,15 @@ func (s *Sketch) resolveCurveRadius(e *Element) (*big.Float, bool) {
 			radius.Sub(distFromCurve, &dv)
 			return &radius, true
 		}
+
+		// Arcs with a solved end point, such as the pieces of a split arc
+		if e.elementType == Arc {
+			for _, end := range e.children[1:] {
+				if s.isElementSolved(end) {
+					return end.element.AsPoint().DistanceTo(e.children[0].element.AsPoint()), true
+				}
+			}
+		}
 	}
 
 	return nil, false
@@ -461,7 +596,8 @@ func (s *Sketch) Solve() error {
 	// lastUnresolved := 0
 	s.checkCircleConstraints()
 	lastUnresolved, lastUnsolved := s.resolveConstraints()
-	for numUnresolved, numUnsolved := lastUnresolved+1, lastUnresolved; /*numUnsolved > 0 ||*/ numUnresolved > 0; numUnresolved, numUnsolved = s.resolveConstraints() {
+	// Keep solving while constraints remain unresolved or the last pass resolved new constraints that still need a solve
+	for numUnresolved, numUnsolved := lastUnresolved+1, lastUnresolved; /*numUnsolved > 0 ||*/ numUnresolved > 0 || numUnresolved < lastUnresolved; numUnresolved, numUnsolved = s.resolveConstraints() {
 		if lastUnsolved == numUnsolved && lastUnresolved == numUnresolved {
 			utils.Logger.Debug().
 				Int("last unsolved", lastUnsolved).
@@ -491,6 +627,17 @@ func (s *Sketch) Solve() error {
 	}
 	s.passes += passes
 
+	// Inequalities are enforced numerically once everything else is solved
+	if inequalityState := s.solveInequalities(); inequalityState != solver.Solved {
+		solveState = inequalityState
+	} else if s.isFreedomInLoops() && s.isEveryConstraintResolved() {
+		// The graph can't see the freedom removed by area and perimeter constraints, so the sketch is
+		// solved once the numeric solver meets them along with the internal constraints of every other
+		// constraint, as long as the graph left no other freedom. Constraints which couldn't be resolved
+		// have no internal constraints to meet.
+		solveState = solver.Solved
+	}
+
 	var copyElements func(e *Element, sketch *core.SketchGraph)
 	copyElements = func(e *Element, sketch *core.SketchGraph) {
 		if el, ok := s.sketch.GetElement(e.element.GetID()); ok {
@@ -507,6 +654,7 @@ func (s *Sketch) Solve() error {
 		copyElements(e, s.sketch)
 		e.valuesFromSketch(s)
 	}
+	s.updateReferences()
 
 	if s.sketch.Conflicting().Count() > 0 {
 		log.Error().Str("Conflicting Constraints", s.sketch.Conflicting().String()).Msg("Found conflicting constraints")
@@ -517,10 +665,24 @@ func (s *Sketch) Solve() error {
 	case solver.Solved:
 		return nil
 	default:
+		if err := s.constraintErrors(); err != nil {
+			return fmt.Errorf("failed to solve completely: %w", err)
+		}
 		return errors.New("failed to solve completely")
 	}
 }
 
+// constraintErrors returns the reasons constraints couldn't be resolved, if any are known
+func (s *Sketch) constraintErrors() error {
+	errs := make([]error, 0)
+	for _, c := range s.constraints {
+		if c.err != nil {
+			errs = append(errs, c.err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (s *Sketch) ConflictingConstraints() []*Constraint {
 	conflicting := make([]*Constraint, 0)
 	for _, c := range s.constraints {
@@ -542,6 +704,9 @@ func (s *Sketch) calculateRectangle(scale float64) (float64, float64, float64, f
 	maxY := math.MaxFloat64 * -1
 
 	for _, e := range s.Elements {
+		if e.external != nil {
+			continue
+		}
 		lX, lY, hX, hY := e.minMaxXY()
 		if lX < minX {
 			minX = lX
@@ -3,7 +3,9 @@ package dlineate
 import (
 	"bytes"
 	"errors"
+	"math"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/marcuswu/dlineate/internal/constraint"
@@ -41,7 +43,7 @@ func TestResolveConstraint(t *testing.T) {
 		{"Angle constraint", angle, Resolved},
 		{"Parallel constraint", parallel, Resolved},
 		{"Perpendicular constraint", perpendicular, Resolved},
-		{"Ratio constraint", s.AddRatioConstraint(l1, a1, 2), Solved},
+		{"Ratio constraint", s.AddRatioConstraint(l1, a1, 2), Unresolved}, // The arc radius is needed to set its length
 		{"Midpoint constraint", s.AddMidpointConstraint(p1, l1), Resolved},
 		{"Tangent constraint", tangent, Unresolved},
 	}
@@ -224,3 +226,1370 @@ func TestSolve(t *testing.T) {
 	assert.Nil(t, err, "Expect no error from WriteImage")
 	assert.Contains(t, b.String(), "svg", "wrote an svg")
 }
+
+func TestSolveAxisDistances(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0.1, 0.1, 2.8, 4.3)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalDistanceConstraint(l1.Start(), l1.End(), 3)
+	s.AddVerticalDistanceConstraint(l1.Start(), l1.End(), 4)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	values := l1.Values()
+	assert.InDelta(t, 0, values[0], utils.StandardCompare, "Line starts at the origin")
+	assert.InDelta(t, 0, values[1], utils.StandardCompare, "Line starts at the origin")
+	assert.InDelta(t, 3, values[2], utils.StandardCompare, "Line end is 3 right of start")
+	assert.InDelta(t, 4, values[3], utils.StandardCompare, "Line end is 4 above start")
+
+	// Negative distances put the end left of and below the start, even when it is drawn on the other side
+	s = NewSketch()
+	l1 = s.AddLine(0.1, 0.1, 2.8, 4.3)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalDistanceConstraint(l1.Start(), l1.End(), -3)
+	s.AddVerticalDistanceConstraint(l1.Start(), l1.End(), -4)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, -3, -4}, l1.Values(), utils.StandardCompare, "Line end is 3 left of and 4 below start")
+
+	// Distances from points left of the Y axis
+	s = NewSketch()
+	p1 := s.AddPoint(-4.9, 0.1)
+	p2 := s.AddPoint(2.1, 0.1)
+	s.AddCoincidentConstraint(p1, s.XAxis)
+	s.AddHorizontalDistanceConstraint(s.Origin, p1, -5)
+	s.AddCoincidentConstraint(p2, s.XAxis)
+	s.AddHorizontalDistanceConstraint(p1, p2, 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{-5, 0}, p1.Values(), utils.StandardCompare, "Point is 5 left of the origin")
+	assert.InDeltaSlice(t, []float64{-2, 0}, p2.Values(), utils.StandardCompare, "Point is 3 right of the other point")
+
+	// Horizontal distance from a point to a line at 45 degrees
+	s = NewSketch()
+	l1 = s.AddLine(0, 0, 1, 1.1)
+	p1 = s.AddPoint(2.1, 0.1)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddAngleConstraint(s.XAxis, l1, 45, false)
+	s.AddDistanceConstraint(l1, nil, 2)
+	s.AddCoincidentConstraint(p1, s.XAxis)
+	s.AddHorizontalDistanceConstraint(l1, p1, 2)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	values = p1.Values()
+	assert.InDelta(t, 2, values[0], utils.StandardCompare, "Point is 2 right of the line")
+	assert.InDelta(t, 0, values[1], utils.StandardCompare, "Point is on the X axis")
+}
+
+func TestSolveArcAngle(t *testing.T) {
+	s := NewSketch()
+	a1 := s.AddArc(0.1, 0.1, 2.1, 0.2, 0.2, -1.9)
+	s.AddCoincidentConstraint(a1.Center(), s.Origin)
+	s.AddDistanceConstraint(a1, nil, 2)
+	s.AddCoincidentConstraint(a1.Start(), s.XAxis)
+	s.AddArcAngleConstraint(a1, 90)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	values := a1.Values()
+	assert.InDelta(t, 2, values[2], utils.StandardCompare, "Arc starts on the X axis")
+	assert.InDelta(t, 0, values[3], utils.StandardCompare, "Arc starts on the X axis")
+	assert.InDelta(t, 0, values[4], utils.StandardCompare, "Arc ends on the Y axis")
+	assert.InDelta(t, -2, values[5], utils.StandardCompare, "Arc ends on the Y axis")
+	sweep, ok := a1.arcSweep()
+	assert.True(t, ok, "Arcs have a sweep angle")
+	assert.InDelta(t, math.Pi/2, sweep, utils.StandardCompare, "Arc sweeps 90 degrees")
+
+	// The chord of a sweep is the chord of its complement, so the center's side of the chord picks the sweep
+	for _, tt := range []struct {
+		name  string
+		angle float64
+		drawn float64
+		end   []float64
+	}{
+		{"Arc over half a circle drawn under half", 270, -1.9, []float64{0, 2}},
+		{"Arc under half a circle drawn over half", 90, 1.9, []float64{0, -2}},
+	} {
+		s = NewSketch()
+		a1 = s.AddArc(0.1, 0.1, 2.1, 0.2, 0.2, tt.drawn)
+		s.AddCoincidentConstraint(a1.Center(), s.Origin)
+		s.AddDistanceConstraint(a1, nil, 2)
+		s.AddCoincidentConstraint(a1.Start(), s.XAxis)
+		s.AddArcAngleConstraint(a1, tt.angle)
+
+		err = s.Solve()
+		assert.Nil(t, err, "%s: Expected successful solve", tt.name)
+		assert.InDeltaSlice(t, tt.end, a1.Values()[4:], utils.StandardCompare, "%s: Arc ends on the Y axis", tt.name)
+		sweep, _ = a1.arcSweep()
+		assert.InDelta(t, tt.angle*math.Pi/180, sweep, utils.StandardCompare, "%s: Arc has the sweep", tt.name)
+	}
+
+	// A length which is too long for the radius is reported by the solve
+	s = NewSketch()
+	a1 = s.AddArc(0.1, 0.1, 2.1, 0.2, 0.2, -1.9)
+	s.AddCoincidentConstraint(a1.Center(), s.Origin)
+	s.AddCoincidentConstraint(a1.Start(), s.XAxis)
+	s.AddArcLengthConstraint(a1, 7)
+	s.AddDistanceConstraint(a1, nil, 1)
+
+	err = s.Solve()
+	assert.ErrorContains(t, err, "must be less than 2pi", "Solve reports the arc which is too long")
+}
+
+func TestSolveCollinear(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 1.8, 1.1)
+	l2 := s.AddLine(2.5, 1.3, 3.4, 2.1)
+	l3 := s.AddLine(1.1, 0.2, 2.2, -0.1)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddAngleConstraint(s.XAxis, l1, 30, false)
+	s.AddDistanceConstraint(l1, nil, 2)
+	s.AddCollinearConstraint(l1, l2)
+	s.AddDistanceConstraint(l1.End(), l2.Start(), 1)
+	s.AddDistanceConstraint(l2, nil, 1)
+	s.AddCollinearConstraint(l3, s.XAxis)
+	s.AddDistanceConstraint(l3.Start(), s.YAxis, 1)
+	s.AddDistanceConstraint(l3, nil, 2)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	values := l2.Values()
+	angle := math.Pi / 6
+	assert.InDelta(t, 3*math.Cos(angle), values[0], utils.StandardCompare, "Second line starts on the first line")
+	assert.InDelta(t, 3*math.Sin(angle), values[1], utils.StandardCompare, "Second line starts on the first line")
+	assert.InDelta(t, 4*math.Cos(angle), values[2], utils.StandardCompare, "Second line ends on the first line")
+	assert.InDelta(t, 4*math.Sin(angle), values[3], utils.StandardCompare, "Second line ends on the first line")
+	values = l3.Values()
+	assert.InDelta(t, 1, values[0], utils.StandardCompare, "Line is on the X axis")
+	assert.InDelta(t, 0, values[1], utils.StandardCompare, "Line is on the X axis")
+	assert.InDelta(t, 3, values[2], utils.StandardCompare, "Line is on the X axis")
+	assert.InDelta(t, 0, values[3], utils.StandardCompare, "Line is on the X axis")
+}
+
+func TestSolvePointAngle(t *testing.T) {
+	s := NewSketch()
+	p1 := s.AddPoint(2.1, 0.1)
+	p2 := s.AddPoint(0.9, 1.8)
+	s.AddCoincidentConstraint(p1, s.XAxis)
+	s.AddDistanceConstraint(s.Origin, p1, 2)
+	s.AddDistanceConstraint(s.Origin, p2, 2)
+	s.AddPointAngleConstraint(p1, s.Origin, p2, 60)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDelta(t, 2, p1.Values()[0], utils.StandardCompare, "First point is on the X axis")
+	assert.InDelta(t, 0, p1.Values()[1], utils.StandardCompare, "First point is on the X axis")
+	assert.InDelta(t, 1, p2.Values()[0], utils.StandardCompare, "Second point is 60 degrees from the first")
+	assert.InDelta(t, math.Sqrt(3), p2.Values()[1], utils.StandardCompare, "Second point is 60 degrees from the first")
+
+	var b bytes.Buffer
+	err = s.WriteImage(&b, 100, 100)
+	assert.Nil(t, err, "Expect no error from WriteImage")
+	assert.Equal(t, 2, strings.Count(b.String(), "<line"), "Only the axes are drawn as lines")
+}
+
+func TestSolveInequalities(t *testing.T) {
+	// A minimum length which is met is left alone
+	s := NewSketch()
+	l1 := s.AddLine(0.1, 0.1, 2.9, 0.2)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddDistanceConstraint(l1, nil, 3)
+	min, _ := s.AddMinDistanceConstraint(l1, nil, 2)
+	max, _ := s.AddMaxDistanceConstraint(l1, nil, 3)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDelta(t, 3, l1.Values()[2], utils.StandardCompare, "Line length is unchanged")
+	assert.Equal(t, Solved, min.state, "Minimum length is solved")
+	assert.False(t, min.IsActive(), "Minimum length is not at its limit")
+	assert.True(t, max.IsActive(), "Maximum length is at its limit")
+
+	// A minimum length which isn't met moves the free end to the limit
+	s = NewSketch()
+	l1 = s.AddLine(0.1, 0.1, 2.9, 0.2)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	min, _ = s.AddMinDistanceConstraint(l1, nil, 5)
+
+	s.Solve()
+	assert.InDelta(t, 5, l1.Values()[2], math.Sqrt(utils.StandardCompare), "Line is lengthened to its minimum")
+	assert.InDelta(t, 0, l1.Values()[3], math.Sqrt(utils.StandardCompare), "Line stays horizontal")
+	assert.Equal(t, Solved, min.state, "Minimum length is solved")
+	assert.True(t, min.IsActive(), "Minimum length is at its limit")
+}
+
+func TestSolveFillet(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0.1, 0.1, 4, 0.2)
+	l2 := s.AddLine(0.1, 0.1, 1.9, 3.3)
+	s.AddCoincidentConstraint(l1.Start(), l2.Start())
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	arc, err := s.AddFillet(l1, l2, 1)
+	assert.Nil(t, err, "Expected fillet to be added")
+	// The fillet follows the corner as the angle between the lines changes
+	s.AddHorizontalConstraint(l1)
+	s.AddAngleConstraint(l1, l2, 45, false)
+	s.AddDistanceConstraint(l1, nil, 4)
+	s.AddDistanceConstraint(l2, nil, 4)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	// The tangent points are 1 / tan(22.5 degrees) from the corner
+	tangent := 1 / math.Tan(math.Pi/8)
+	values := arc.Values()
+	assert.InDelta(t, tangent, values[0], utils.StandardCompare, "Arc center is on the corner's bisector")
+	assert.InDelta(t, 1, values[1], utils.StandardCompare, "Arc center is on the corner's bisector")
+	assert.InDelta(t, tangent, values[2], utils.StandardCompare, "Arc starts on the first line")
+	assert.InDelta(t, 0, values[3], utils.StandardCompare, "Arc starts on the first line")
+	assert.InDelta(t, tangent*math.Sqrt2/2, values[4], utils.StandardCompare, "Arc ends on the second line")
+	assert.InDelta(t, tangent*math.Sqrt2/2, values[5], utils.StandardCompare, "Arc ends on the second line")
+	assert.InDelta(t, tangent, l1.Values()[0], utils.StandardCompare, "First line starts at the arc")
+	assert.InDelta(t, tangent+4, l1.Values()[2], utils.StandardCompare, "First line has its length")
+}
+
+func TestSolveChamfer(t *testing.T) {
+	for _, useAngle := range []bool{false, true} {
+		s := NewSketch()
+		l1 := s.AddLine(0.1, 0.1, 4, 0.2)
+		l2 := s.AddLine(0.2, 4.1, 0.1, 0.1)
+		s.AddCoincidentConstraint(l1.Start(), l2.End())
+		s.AddCoincidentConstraint(s.Origin, l1.Start())
+		s.AddHorizontalConstraint(l1)
+		s.AddVerticalConstraint(l2)
+		s.AddDistanceConstraint(l1, nil, 4)
+		s.AddDistanceConstraint(l2, nil, 4)
+		var chamfer *Element
+		var err error
+		if useAngle {
+			// An angle of atan(2) from the first line is the same chamfer as distances of 1 and 2
+			chamfer, err = s.AddAngleChamfer(l1, l2, 1, math.Atan(2)*180/math.Pi)
+		} else {
+			chamfer, err = s.AddChamfer(l1, l2, 1, 2)
+		}
+		assert.Nil(t, err, "Expected chamfer to be added")
+
+		err = s.Solve()
+		assert.Nil(t, err, "Expected successful solve")
+		values := chamfer.Values()
+		assert.InDelta(t, 1, values[0], utils.StandardCompare, "Chamfer starts on the first line")
+		assert.InDelta(t, 0, values[1], utils.StandardCompare, "Chamfer starts on the first line")
+		assert.InDelta(t, 0, values[2], utils.StandardCompare, "Chamfer ends on the second line")
+		assert.InDelta(t, 2, values[3], utils.StandardCompare, "Chamfer ends on the second line")
+		assert.InDelta(t, 1, l1.Values()[0], utils.StandardCompare, "First line starts at the chamfer")
+		assert.InDelta(t, 2, l2.Values()[3], utils.StandardCompare, "Second line ends at the chamfer")
+	}
+}
+
+func TestWriteImageConstruction(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 2, 0)
+	s.AddLine(0, 0, 0, 2)
+	c1 := s.AddCircle(1, 1, 0.5)
+	s.AddDistanceConstraint(c1, nil, 0.5)
+	l1.SetConstruction(true)
+	c1.SetConstruction(true)
+
+	var b bytes.Buffer
+	err := s.WriteImage(&b, 100, 100)
+	assert.Nil(t, err, "Expect no error from WriteImage")
+	// The construction line, its two points and the circle's center and outline are dashed
+	assert.Equal(t, 5, strings.Count(b.String(), "stroke-dasharray"), "Construction geometry is dashed")
+}
+
+func TestSolveReferences(t *testing.T) {
+	s := NewSketch()
+	l1 := s.AddLine(0.1, 0.1, 2.9, 0.2)
+	p1 := s.AddPoint(1.1, 2.2)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddDistanceConstraint(l1, nil, 3)
+	s.AddDistanceConstraint(p1, s.XAxis, 2)
+	s.AddDistanceConstraint(p1, s.YAxis, 1)
+	length, _ := s.AddReferenceDistance(l1, nil)
+	distance, _ := s.AddReferenceDistance(p1, l1.End())
+	angle, _ := s.AddReferenceAngle(l1, s.YAxis)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Reference dimensions don't over constrain the sketch")
+	assert.Equal(t, 0, len(s.ConflictingConstraints()), "Reference dimensions don't conflict")
+	assert.Equal(t, Solved, length.state, "Reference dimensions are solved")
+	assert.InDelta(t, 3, length.Value(), utils.StandardCompare, "Line length is measured after solving")
+	assert.InDelta(t, math.Sqrt(8), distance.Value(), utils.StandardCompare, "Point distance is measured after solving")
+	assert.InDelta(t, 90, angle.Value(), utils.StandardCompare, "Angle is measured after solving")
+}
+
+func TestSolveParameters(t *testing.T) {
+	s := NewSketch()
+	s.SetParameter("height", 2)
+	s.SetParameterExpression("width", "2 * height + 1")
+	l1 := s.AddLine(0.1, 0.1, 4, 0.2)
+	l2 := s.AddLine(4, 0.2, 4.1, 2.1)
+	c1 := s.AddCircle(2, 3, 0.9)
+	s.AddCoincidentConstraint(l1.Start(), s.Origin)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddVerticalConstraint(l2)
+	s.AddDistanceExpression(l1, nil, "width")
+	s.AddDistanceExpression(l2, nil, "height")
+	s.AddDistanceExpression(c1, nil, "height / 2")
+	s.AddTangentConstraint(l1, c1)
+	s.AddDistanceConstraint(c1.Center(), s.YAxis, 2)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDelta(t, 5, l1.Values()[2], utils.StandardCompare, "Width is evaluated from the height")
+	assert.InDelta(t, 2, l2.Values()[3], utils.StandardCompare, "Height is the parameter value")
+	assert.InDelta(t, 1, c1.Values()[1], utils.StandardCompare, "Circle is tangent to the line")
+
+	// Changing a parameter solves the sketch again, including constraints that depend on it indirectly
+	err = s.SetParameter("height", 3)
+	assert.Nil(t, err, "Expected successful solve after changing a parameter")
+	assert.InDelta(t, 7, l1.Values()[2], utils.StandardCompare, "Width is updated")
+	assert.InDelta(t, 7, l2.Values()[0], utils.StandardCompare, "Second line moves with the first")
+	assert.InDelta(t, 3, l2.Values()[3], utils.StandardCompare, "Height is updated")
+	assert.InDelta(t, 1.5, c1.Values()[2], utils.StandardCompare, "Circle radius is updated")
+	assert.InDelta(t, 1.5, c1.Values()[1], utils.StandardCompare, "Circle stays tangent to the line")
+}
+
+func TestSolveUnits(t *testing.T) {
+	s := NewSketch()
+	s.SetUnits(Inch, Radian)
+	l1 := s.AddLine(0.1, 0.1, 2.9, 0.2)
+	l2 := s.AddLine(2.9, 0.2, 3.1, 2.1)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddHorizontalConstraint(l1)
+	length := s.AddDistanceConstraint(l1, nil, 3)
+	s.AddDistanceConstraint(l2, nil, s.Length(Millimeters(50.8)))
+	angle, _ := s.AddAngleConstraint(l1, l2, math.Pi/2, false)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Sketch in inches and radians solves")
+	assert.InDelta(t, 3, length.Value(), utils.StandardCompare, "Distance value is in inches")
+	assert.InDelta(t, math.Pi/2, angle.Value(), utils.StandardCompare, "Angle value is in radians")
+	assert.InDeltaSlice(t, []float64{3, 0, 3, 2}, l2.Values(), utils.StandardCompare, "Line values are in inches")
+	assert.InDeltaSlice(t, []float64{76.2, 0, 76.2, 50.8}, l2.values, utils.StandardCompare, "Line is solved in millimeters")
+
+	// Typed dimensions in a sketch using millimeters and degrees
+	s = NewSketch()
+	l1 = s.AddLine(0.1, 0.1, 70, 2)
+	l2 = s.AddLine(70, 2, 75, 40)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddHorizontalConstraint(l1)
+	length = s.AddDistanceConstraintValue(l1, nil, Inches(3))
+	s.AddDistanceConstraintValue(l2, nil, Centimeters(5.08))
+	angle, err = s.AddAngleConstraintValue(l1, l2, Radians(math.Pi/2), false)
+	assert.Nil(t, err, "Typed angle constraint is valid")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Sketch with typed dimensions solves")
+	assert.InDelta(t, 76.2, length.Value(), utils.StandardCompare, "Typed lengths are converted to the sketch's unit")
+	assert.InDelta(t, 90, angle.Value(), utils.StandardCompare, "Typed angles are converted to the sketch's unit")
+	assert.InDeltaSlice(t, []float64{76.2, 0, 76.2, 50.8}, l2.Values(), utils.StandardCompare, "Line is solved from typed dimensions")
+}
+
+func TestSetWorkplane(t *testing.T) {
+	s := NewSketch()
+	assert.Equal(t, NewVector(0, 0, 1), s.Workplane().Normal(), "Sketches default to the XY plane")
+
+	parallel := NewWorkPlane(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(2, 0, 0))
+	assert.NotNil(t, parallel.Validate(), "Parallel directions are rejected")
+	empty := NewWorkPlane(NewVector(0, 0, 0), NewVector(0, 0, 0), NewVector(0, 1, 0))
+	assert.NotNil(t, empty.Validate(), "Directions must have a length")
+	var missing *Workplane
+	assert.NotNil(t, missing.Validate(), "Workplanes must not be nil")
+	assert.NotNil(t, s.UseWorkplane(parallel), "Parallel directions are rejected")
+	assert.NotNil(t, s.UseWorkplane(empty), "Directions must have a length")
+	assert.NotNil(t, s.UseWorkplane(nil), "Workplanes must not be nil")
+	s.SetWorkplane(parallel)
+	assert.Equal(t, NewVector(1, 0, 0), s.Workplane().XDir(), "Rejected workplanes leave the workplane unchanged")
+
+	plane := NewWorkPlane(NewVector(1, 2, 3), NewVector(0, 2, 0), NewVector(1, 1, 1))
+	assert.Nil(t, plane.Validate(), "Workplane is valid")
+	assert.Nil(t, s.UseWorkplane(plane), "Workplane is set")
+	wp := s.Workplane()
+	assert.InDelta(t, 1, wp.XDir().Magnitude(), utils.StandardCompare, "x direction is normalized")
+	assert.InDelta(t, 1, wp.YDir().Magnitude(), utils.StandardCompare, "y direction is normalized")
+	assert.InDelta(t, 0, wp.XDir().Dot(wp.YDir()), utils.StandardCompare, "directions are perpendicular")
+	assert.InDelta(t, math.Sqrt2/2, wp.YDir().X, utils.StandardCompare, "y direction stays in the plane")
+	assert.InDelta(t, math.Sqrt2/2, wp.YDir().Z, utils.StandardCompare, "y direction stays in the plane")
+
+	v := s.To3D(3, 4)
+	assert.InDelta(t, 1+(4*math.Sqrt2/2), v.X, utils.StandardCompare, "3D X")
+	assert.InDelta(t, 5, v.Y, utils.StandardCompare, "3D Y")
+	assert.InDelta(t, 3+(4*math.Sqrt2/2), v.Z, utils.StandardCompare, "3D Z")
+
+	x, y := s.ProjectTo2D(v.Add(wp.Normal().Scale(7)))
+	assert.InDelta(t, 3, x, utils.StandardCompare, "Projection removes the normal offset")
+	assert.InDelta(t, 4, y, utils.StandardCompare, "Projection removes the normal offset")
+}
+
+func TestSolveExternal(t *testing.T) {
+	s := NewSketch()
+	s.UseWorkplane(NewWorkPlane(NewVector(0, 0, 5), NewVector(0, 1, 0), NewVector(0, 0, 1)))
+	ext := s.AddExternalLine(NewVector(0, 1, 6), NewVector(0, 5, 7))
+	l1 := s.AddLine(0.2, 0.1, 1.1, 3)
+	l2 := s.AddLine(1.1, 3, 0.5, 2.9)
+	s.AddCoincidentConstraint(l1.Start(), s.Origin)
+	s.AddCoincidentConstraint(l1.End(), ext.Start())
+	s.AddCoincidentConstraint(l2.Start(), l1.End())
+	s.AddDistanceConstraint(l2, nil, 2)
+	s.AddPerpendicularConstraint(l2, ext)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Sketch with external geometry solves")
+	assert.InDeltaSlice(t, []float64{1, 1, 5, 2}, ext.Values(), utils.StandardCompare, "External line doesn't move")
+	assert.InDeltaSlice(t, []float64{0, 0, 1, 1}, l1.Values(), utils.StandardCompare, "Line ends at the external line")
+	values := l2.Values()
+	assert.InDelta(t, 0, ((values[2]-values[0])*4)+(values[3]-values[1]), utils.StandardCompare, "Line is perpendicular to the external line")
+
+	err = s.UpdateExternal(ext, NewVector(0, 2, 6), NewVector(0, 6, 9))
+	assert.Nil(t, err, "Sketch solves after the external line moves")
+	assert.InDeltaSlice(t, []float64{2, 1, 6, 4}, ext.Values(), utils.StandardCompare, "External line is projected again")
+	assert.InDeltaSlice(t, []float64{0, 0, 2, 1}, l1.Values(), utils.StandardCompare, "Line follows the external line")
+	values = l2.Values()
+	assert.InDelta(t, 0, ((values[2]-values[0])*4)+((values[3]-values[1])*3), utils.StandardCompare, "Line stays perpendicular")
+	assert.InDelta(t, 2, math.Hypot(values[2]-values[0], values[3]-values[1]), utils.StandardCompare, "Line keeps its length")
+
+	var b bytes.Buffer
+	s.WriteImage(&b, 500, 200)
+	assert.Equal(t, 4, strings.Count(b.String(), "<line"), "Only the axes and the two sketch lines are exported")
+}
+
+func TestProfiles(t *testing.T) {
+	// A square drawn clockwise with a rounded corner, a circular hole and a triangle inside the hole
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 0, 10)
+	l2 := s.AddLine(0, 10, 10, 10)
+	l3 := s.AddLine(10, 10, 10, 0)
+	l4 := s.AddLine(10, 0, 0, 0)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddCoincidentConstraint(l2.End(), l3.Start())
+	s.AddCoincidentConstraint(l3.End(), l4.Start())
+	s.AddCoincidentConstraint(l4.End(), l1.Start())
+	_, err := s.AddFillet(l2, l3, 2)
+	assert.Nil(t, err, "Fillet is added")
+	hole := s.AddCircle(5, 5, 3)
+	t1 := s.AddLine(4, 4, 6, 4)
+	t2 := s.AddLine(6, 4, 5, 6)
+	t3 := s.AddLine(5, 6, 4, 4)
+	s.AddCoincidentConstraint(t1.End(), t2.Start())
+	s.AddCoincidentConstraint(t2.End(), t3.Start())
+	s.AddCoincidentConstraint(t3.End(), t1.Start())
+	s.AddLine(-5, 5, 15, 5).SetConstruction(true)
+
+	profiles, err := s.Profiles()
+	assert.Nil(t, err, "Profiles are found")
+	assert.Equal(t, 2, len(profiles), "The square and the triangle are profiles")
+	var square, triangle Profile
+	for _, p := range profiles {
+		if len(p.Outer.Edges) == 5 {
+			square = p
+		} else {
+			triangle = p
+		}
+	}
+	assert.Equal(t, 5, len(square.Outer.Edges), "Square has four lines and a fillet")
+	assert.True(t, square.Outer.CounterClockwise, "Outer loops are counter-clockwise")
+	assert.InDelta(t, 100-(4-math.Pi), square.Outer.signedArea(), utils.StandardCompare, "Square area less the rounded corner")
+	assert.Equal(t, 1, len(square.Holes), "Square has a hole")
+	assert.Equal(t, hole, square.Holes[0].Edges[0].Element, "The circle is the hole")
+	assert.False(t, square.Holes[0].CounterClockwise, "Holes are clockwise")
+	assert.True(t, square.Holes[0].Edges[0].Reversed, "Clockwise circles are reversed")
+	assert.Equal(t, 3, len(triangle.Outer.Edges), "Triangle inside the hole is a profile")
+	assert.Equal(t, 0, len(triangle.Holes), "Triangle has no holes")
+	assert.InDelta(t, 2, triangle.Outer.signedArea(), utils.StandardCompare, "Triangle area")
+
+	// Each edge of a loop starts where the previous edge ends
+	edges := square.Outer.Edges
+	for i, edge := range edges {
+		_, _, ex, ey := edges[(i+len(edges)-1)%len(edges)].points()
+		sx, sy, _, _ := edge.points()
+		assert.InDelta(t, ex, sx, utils.StandardCompare, "Edges are connected")
+		assert.InDelta(t, ey, sy, utils.StandardCompare, "Edges are connected")
+	}
+
+	// Open chains
+	s = NewSketch()
+	l1 = s.AddLine(0, 0, 1, 0)
+	l2 = s.AddLine(1, 0, 1, 1)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	_, err = s.Profiles()
+	assert.NotNil(t, err, "Open chains are reported")
+
+	// Branching vertices
+	l3 = s.AddLine(1, 1, 0, 0)
+	s.AddCoincidentConstraint(l2.End(), l3.Start())
+	s.AddCoincidentConstraint(l3.End(), l1.Start())
+	profiles, err = s.Profiles()
+	assert.Nil(t, err, "Triangle is closed")
+	assert.Equal(t, 1, len(profiles), "Triangle is a profile")
+	l4 = s.AddLine(1, 0, 2, 0)
+	s.AddCoincidentConstraint(l4.Start(), l1.End())
+	l5 := s.AddLine(2, 0, 1, 0)
+	s.AddCoincidentConstraint(l4.End(), l5.Start())
+	s.AddCoincidentConstraint(l5.End(), l1.End())
+	_, err = s.Profiles()
+	assert.NotNil(t, err, "Branching vertices are reported")
+
+	// Self intersections
+	s = NewSketch()
+	l1 = s.AddLine(0, 0, 1, 1)
+	l2 = s.AddLine(1, 1, 1, 0)
+	l3 = s.AddLine(1, 0, 0, 1)
+	l4 = s.AddLine(0, 1, 0, 0)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddCoincidentConstraint(l2.End(), l3.Start())
+	s.AddCoincidentConstraint(l3.End(), l4.Start())
+	s.AddCoincidentConstraint(l4.End(), l1.Start())
+	_, err = s.Profiles()
+	assert.NotNil(t, err, "Self intersecting loops are reported")
+
+	// Loops crossing each other
+	s = NewSketch()
+	s.AddCircle(0, 0, 2)
+	s.AddCircle(3, 0, 2)
+	_, err = s.Profiles()
+	assert.NotNil(t, err, "Crossing loops are reported")
+}
+
+// addClosedLoop adds lines through the points joined into a closed loop
+func addClosedLoop(s *Sketch, points ...float64) []*Element {
+	lines := make([]*Element, 0)
+	count := len(points) / 2
+	for i := 0; i < count; i++ {
+		j := (i + 1) % count
+		lines = append(lines, s.AddLine(points[i*2], points[(i*2)+1], points[j*2], points[(j*2)+1]))
+	}
+	for i, l := range lines {
+		s.AddCoincidentConstraint(l.End(), lines[(i+1)%count].Start())
+	}
+	return lines
+}
+
+func TestProfileProperties(t *testing.T) {
+	s := NewSketch()
+	addClosedLoop(s, 1, 1, 5, 1, 5, 3, 1, 3)
+	profiles, err := s.Profiles()
+	assert.Nil(t, err, "Rectangle profile")
+	p := profiles[0]
+	ixx, iyy, ixy := p.SecondMoments()
+	cx, cy := p.Centroid()
+	assert.InDelta(t, 8, p.Area(), utils.StandardCompare, "Rectangle area")
+	assert.InDelta(t, 12, p.Perimeter(), utils.StandardCompare, "Rectangle perimeter")
+	assert.InDelta(t, 3, cx, utils.StandardCompare, "Rectangle centroid")
+	assert.InDelta(t, 2, cy, utils.StandardCompare, "Rectangle centroid")
+	assert.InDelta(t, 4*8/12.0, ixx, utils.StandardCompare, "Rectangle Ixx is bh^3/12")
+	assert.InDelta(t, 2*64/12.0, iyy, utils.StandardCompare, "Rectangle Iyy is hb^3/12")
+	assert.InDelta(t, 0, ixy, utils.StandardCompare, "Rectangle is symmetric")
+
+	s.SetUnits(Centimeter, Degree)
+	assert.InDelta(t, 0.08, p.Area(), utils.StandardCompare, "Area is in the sketch unit")
+	assert.InDelta(t, 1.2, p.Perimeter(), utils.StandardCompare, "Perimeter is in the sketch unit")
+	ixx, _, _ = p.SecondMoments()
+	assert.InDelta(t, 4*8/12.0/10000, ixx, utils.StandardCompare, "Second moments are in the sketch unit")
+
+	// Right triangle
+	s = NewSketch()
+	addClosedLoop(s, 0, 0, 3, 0, 0, 6)
+	profiles, _ = s.Profiles()
+	p = profiles[0]
+	ixx, iyy, ixy = p.SecondMoments()
+	cx, cy = p.Centroid()
+	assert.InDelta(t, 9, p.Area(), utils.StandardCompare, "Triangle area")
+	assert.InDelta(t, 1, cx, utils.StandardCompare, "Triangle centroid")
+	assert.InDelta(t, 2, cy, utils.StandardCompare, "Triangle centroid")
+	assert.InDelta(t, 18, ixx, utils.StandardCompare, "Triangle Ixx is bh^3/36")
+	assert.InDelta(t, 4.5, iyy, utils.StandardCompare, "Triangle Iyy is hb^3/36")
+	assert.InDelta(t, -4.5, ixy, utils.StandardCompare, "Triangle Ixy is -b^2h^2/72")
+
+	// Semicircle from an arc and a line
+	s = NewSketch()
+	a := s.AddArc(0, 0, -2, 0, 2, 0)
+	l := s.AddLine(2, 0, -2, 0)
+	s.AddCoincidentConstraint(a.End(), l.Start())
+	s.AddCoincidentConstraint(l.End(), a.Start())
+	profiles, err = s.Profiles()
+	assert.Nil(t, err, "Semicircle profile")
+	p = profiles[0]
+	ixx, iyy, ixy = p.SecondMoments()
+	cx, cy = p.Centroid()
+	assert.InDelta(t, 2*math.Pi, p.Area(), utils.StandardCompare, "Semicircle area")
+	assert.InDelta(t, (2*math.Pi)+4, p.Perimeter(), utils.StandardCompare, "Semicircle perimeter")
+	assert.InDelta(t, 0, cx, utils.StandardCompare, "Semicircle centroid")
+	assert.InDelta(t, 8/(3*math.Pi), cy, utils.StandardCompare, "Semicircle centroid is 4r/3pi above the base")
+	assert.InDelta(t, 16*((math.Pi/8)-(8/(9*math.Pi))), ixx, utils.StandardCompare, "Semicircle Ixx")
+	assert.InDelta(t, 2*math.Pi, iyy, utils.StandardCompare, "Semicircle Iyy is pi r^4/8")
+	assert.InDelta(t, 0, ixy, utils.StandardCompare, "Semicircle is symmetric")
+
+	// Quarter circle away from the origin
+	s = NewSketch()
+	a = s.AddArc(1, 2, 1, 4, 3, 2)
+	l1 := s.AddLine(3, 2, 1, 2)
+	l2 := s.AddLine(1, 2, 1, 4)
+	s.AddCoincidentConstraint(a.End(), l1.Start())
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddCoincidentConstraint(l2.End(), a.Start())
+	profiles, err = s.Profiles()
+	assert.Nil(t, err, "Quarter circle profile")
+	p = profiles[0]
+	ixx, iyy, ixy = p.SecondMoments()
+	cx, cy = p.Centroid()
+	area := math.Pi
+	offset := 8 / (3 * math.Pi)
+	assert.InDelta(t, area, p.Area(), utils.StandardCompare, "Quarter circle area")
+	assert.InDelta(t, 1+offset, cx, utils.StandardCompare, "Quarter circle centroid is 4r/3pi from the corner")
+	assert.InDelta(t, 2+offset, cy, utils.StandardCompare, "Quarter circle centroid is 4r/3pi from the corner")
+	assert.InDelta(t, (math.Pi*16/16)-(area*offset*offset), ixx, utils.StandardCompare, "Quarter circle Ixx")
+	assert.InDelta(t, (math.Pi*16/16)-(area*offset*offset), iyy, utils.StandardCompare, "Quarter circle Iyy")
+	assert.InDelta(t, (16/8.0)-(area*offset*offset), ixy, utils.StandardCompare, "Quarter circle Ixy")
+
+	// Annulus
+	s = NewSketch()
+	s.AddCircle(2, -1, 3)
+	s.AddCircle(2, -1, 1)
+	profiles, err = s.Profiles()
+	assert.Nil(t, err, "Annulus profile")
+	assert.Equal(t, 1, len(profiles), "Inner circle is a hole")
+	p = profiles[0]
+	ixx, iyy, ixy = p.SecondMoments()
+	cx, cy = p.Centroid()
+	assert.InDelta(t, 8*math.Pi, p.Area(), utils.StandardCompare, "Annulus area")
+	assert.InDelta(t, 8*math.Pi, p.Perimeter(), utils.StandardCompare, "Annulus perimeter")
+	assert.InDelta(t, 9*math.Pi, p.Outer.Area(), utils.StandardCompare, "Outer loop area")
+	assert.InDelta(t, 2, cx, utils.StandardCompare, "Annulus centroid")
+	assert.InDelta(t, -1, cy, utils.StandardCompare, "Annulus centroid")
+	assert.InDelta(t, 20*math.Pi, ixx, utils.StandardCompare, "Annulus Ixx is pi (R^4 - r^4) / 4")
+	assert.InDelta(t, 20*math.Pi, iyy, utils.StandardCompare, "Annulus Iyy is pi (R^4 - r^4) / 4")
+	assert.InDelta(t, 0, ixy, utils.StandardCompare, "Annulus is symmetric")
+}
+
+func TestSolveAreaConstraints(t *testing.T) {
+	// A rectangle with a fixed width has its height driven by its area
+	s := NewSketch()
+	lines := addClosedLoop(s, 0, 0, 10, 0, 10, 3, 0, 3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddDistanceConstraint(lines[0], nil, 10)
+	area, _ := s.AddAreaConstraint(lines, 50)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.Equal(t, Solved, area.state, "Area constraint is solved")
+	assert.InDelta(t, 5, lines[1].Values()[3], math.Sqrt(utils.StandardCompare), "Height is driven by the area")
+	profiles, _ := s.Profiles()
+	assert.InDelta(t, 50, profiles[0].Area(), math.Sqrt(utils.StandardCompare), "Rectangle has the constrained area")
+
+	// A perimeter in the sketch's units drives the height the same way
+	s = NewSketch()
+	s.SetUnits(Centimeter, Degree)
+	lines = addClosedLoop(s, 0, 0, 1, 0, 1, 0.3, 0, 0.3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddDistanceConstraint(lines[0], nil, 1)
+	s.AddPerimeterConstraint(lines, 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDelta(t, 0.5, lines[1].Values()[3], math.Sqrt(utils.StandardCompare), "Height is driven by the perimeter")
+
+	// Meeting the area isn't a solve while other constraints can't be resolved
+	s = NewSketch()
+	lines = addClosedLoop(s, 0, 0, 10, 0, 10, 3, 0, 3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddDistanceConstraint(lines[0], nil, 10)
+	s.AddAreaConstraint(lines, 50)
+	ratio := s.AddRatioConstraint(s.AddLine(12, 0, 14, 1), s.AddLine(12, 2, 15, 3), 2)
+
+	err = s.Solve()
+	assert.NotNil(t, err, "Sketch with an unresolved constraint isn't solved")
+	assert.Equal(t, Unresolved, ratio.state, "Ratio of lines without a length stays unresolved")
+
+	// Or while the area can't be met along with the other constraints
+	s = NewSketch()
+	lines = addClosedLoop(s, 0, 0, 10, 0, 10, 3, 0, 3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddDistanceConstraint(lines[0], nil, 10)
+	s.AddDistanceConstraint(lines[1], nil, 3)
+	area, _ = s.AddAreaConstraint(lines, 50)
+
+	err = s.Solve()
+	assert.NotNil(t, err, "Sketch with an unmet area isn't solved")
+	assert.NotEqual(t, Solved, area.state, "Unmet area isn't solved")
+
+	// Or while the rectangle has more freedom than the area removes
+	s = NewSketch()
+	lines = addClosedLoop(s, 0, 0, 10, 0, 10, 3, 0, 3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddAreaConstraint(lines, 50)
+
+	err = s.Solve()
+	assert.NotNil(t, err, "Under constrained rectangle with an area isn't solved")
+
+	// Or while the freedom is in elements the area isn't connected to
+	s = NewSketch()
+	lines = addClosedLoop(s, 0, 0, 10, 0, 10, 3, 0, 3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddDistanceConstraint(lines[0], nil, 10)
+	s.AddDistanceConstraint(lines[1], nil, 5)
+	s.AddAreaConstraint(lines, 50)
+	p := s.AddPoint(5, 0)
+	s.AddDistanceConstraint(p, s.XAxis, 0)
+
+	err = s.Solve()
+	assert.NotNil(t, err, "Sketch with a point free of the area isn't solved")
+
+	// Meeting the area leaves elements it isn't connected to where they are
+	s = NewSketch()
+	lines = addClosedLoop(s, 0, 0, 10, 0, 10, 3, 0, 3)
+	s.AddCoincidentConstraint(s.Origin, lines[0].Start())
+	s.AddHorizontalConstraint(lines[0])
+	s.AddVerticalConstraint(lines[1])
+	s.AddHorizontalConstraint(lines[2])
+	s.AddVerticalConstraint(lines[3])
+	s.AddDistanceConstraint(lines[0], nil, 10)
+	s.AddAreaConstraint(lines, 50)
+	p = s.AddPoint(5, 0)
+	s.AddDistanceConstraint(p, s.XAxis, 0)
+
+	s.Solve()
+	assert.InDelta(t, 5, lines[1].Values()[3], math.Sqrt(utils.StandardCompare), "Height is driven by the area")
+	assert.InDelta(t, 5, p.Values()[0], utils.StandardCompare, "Point free of the area isn't moved")
+	assert.InDelta(t, 0, p.Values()[1], utils.StandardCompare, "Point free of the area stays on the X axis")
+}
+
+func TestSolveSplitTrimExtend(t *testing.T) {
+	// Trimming then splitting a line
+	s := NewSketch()
+	l1 := s.AddLine(0.1, 0.1, 10, 0.2)
+	l2 := s.AddLine(8.2, -2, 7.9, 2.1)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddVerticalConstraint(l2)
+	s.AddDistanceConstraint(l2, nil, 4)
+	s.AddDistanceConstraint(l2.Start(), s.XAxis, 2)
+	s.AddDistanceConstraint(l2.Start(), s.YAxis, 8)
+	_, err := s.Trim(l1, l2, 9, 0)
+	assert.Nil(t, err, "Expected trim to succeed")
+	piece, err := s.Split(l1, 3, 1)
+	assert.Nil(t, err, "Expected split to succeed")
+	s.AddDistanceConstraint(s.Origin, piece.Start(), 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, 3, 0}, l1.Values(), utils.StandardCompare, "Line ends at the split point")
+	assert.InDeltaSlice(t, []float64{3, 0, 8, 0}, piece.Values(), utils.StandardCompare, "Split piece ends at the trim")
+
+	// A length dimension sets the length of the first piece of a split line
+	s = NewSketch()
+	l1 = s.AddLine(0.1, 0.1, 10, 0.2)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddDistanceConstraint(l1, nil, 10)
+	piece, err = s.Split(l1, 4, 1)
+	assert.Nil(t, err, "Expected split to succeed")
+	s.AddDistanceConstraint(piece, nil, 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, 10, 0}, l1.Values(), utils.StandardCompare, "First piece has the dimensioned length")
+	assert.InDeltaSlice(t, []float64{10, 0, 13, 0}, piece.Values(), utils.StandardCompare, "Second piece continues from the first")
+
+	// A length dimension sets the length of a trimmed line, which places the crossing it was trimmed at
+	s = NewSketch()
+	l1 = s.AddLine(0.1, 0.1, 10, 0.2)
+	l2 = s.AddLine(8.2, -2, 7.9, 2.1)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddDistanceConstraint(l1, nil, 6)
+	s.AddVerticalConstraint(l2)
+	s.AddDistanceConstraint(l2, nil, 4)
+	s.AddDistanceConstraint(l2.Start(), s.XAxis, 2)
+	previous := l1.End()
+	_, err = s.Trim(l1, l2, 9, 0)
+	assert.Nil(t, err, "Expected trim to succeed")
+	assert.True(t, previous.hidden, "Trimmed end point is released from the length dimension")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, 6, 0}, l1.Values(), utils.StandardCompare, "Trimmed line has the dimensioned length")
+	assert.InDeltaSlice(t, []float64{6, -2, 6, 2}, l2.Values(), utils.StandardCompare, "Trim crossing follows the length")
+
+	// Extending a line
+	s = NewSketch()
+	l1 = s.AddLine(0.1, 0.1, 5, 0.2)
+	l2 = s.AddLine(8.2, -2, 7.9, 2.1)
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddVerticalConstraint(l2)
+	s.AddDistanceConstraint(l2, nil, 4)
+	s.AddDistanceConstraint(l2.Start(), s.XAxis, 2)
+	s.AddDistanceConstraint(l2.Start(), s.YAxis, 8)
+	_, err = s.Extend(l1, l2)
+	assert.Nil(t, err, "Expected extend to succeed")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, 8, 0}, l1.Values(), utils.StandardCompare, "Line is extended to the other line")
+
+	// Trimming then splitting an arc
+	s = NewSketch()
+	a := s.AddArc(0.1, 0.1, 0, 5.1, 4.9, 0)
+	c := s.AddLine(-1, 3.1, 6, 2.9)
+	s.AddCoincidentConstraint(s.Origin, a.Center())
+	s.AddDistanceConstraint(a, nil, 5)
+	s.AddCoincidentConstraint(a.Start(), s.YAxis)
+	s.AddCoincidentConstraint(a.End(), s.XAxis)
+	s.AddHorizontalConstraint(c)
+	s.AddDistanceConstraint(c.Start(), s.XAxis, 3)
+	s.AddDistanceConstraint(c.Start(), s.YAxis, 1)
+	s.AddDistanceConstraint(c, nil, 7)
+	_, err = s.Trim(a, c, 4.5, 2)
+	assert.Nil(t, err, "Expected trim to succeed")
+	piece, err = s.Split(a, 3, 4)
+	assert.Nil(t, err, "Expected split to succeed")
+	s.AddDistanceConstraint(piece.Start(), s.YAxis, 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, 0, 5, 3, 4}, a.Values(), utils.StandardCompare, "Arc ends at the split point")
+	assert.InDeltaSlice(t, []float64{0, 0, 3, 4, 4, 3}, piece.Values(), utils.StandardCompare, "Split piece ends at the trim")
+}
+
+func TestSolveIntersectionPoint(t *testing.T) {
+	// A line is placed by dimensioning where it crosses a circle
+	s := NewSketch()
+	c := s.AddCircle(0.1, -0.1, 5.2)
+	l := s.AddLine(-6, 3.2, 6, 2.9)
+	s.AddCoincidentConstraint(s.Origin, c.Center())
+	s.AddDistanceConstraint(c, nil, 5)
+	s.AddHorizontalConstraint(l)
+	s.AddDistanceConstraint(l.Start(), s.YAxis, 6)
+	s.AddDistanceConstraint(l, nil, 12)
+	p, err := s.AddIntersectionPoint(l, c, 1)
+	assert.Nil(t, err, "Expected intersection point to be added")
+	s.AddDistanceConstraint(p, s.YAxis, 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{3, 4}, p.Values(), utils.StandardCompare, "Intersection is on the circle 3 from the axis")
+	assert.InDeltaSlice(t, []float64{-6, 4, 6, 4}, l.Values(), utils.StandardCompare, "Line passes through the intersection")
+
+	// Circles are placed by dimensioning where they cross
+	s = NewSketch()
+	c1 := s.AddCircle(0.1, 0.1, 4.8)
+	c2 := s.AddCircle(7.7, 0.2, 5.1)
+	s.AddCoincidentConstraint(s.Origin, c1.Center())
+	s.AddCoincidentConstraint(c2.Center(), s.XAxis)
+	s.AddDistanceConstraint(c1, nil, 5)
+	s.AddDistanceConstraint(c2, nil, 5)
+	p, err = s.AddIntersectionPoint(c1, c2, 0)
+	assert.Nil(t, err, "Expected intersection point to be added")
+	s.AddDistanceConstraint(p, s.XAxis, 3)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{4, 3}, p.Values(), utils.StandardCompare, "Intersection is on both circles 3 from the axis")
+	assert.InDeltaSlice(t, []float64{8, 0, 5}, c2.Values(), utils.StandardCompare, "Second circle passes through the intersection")
+
+	// Filleted lines are dimensioned from their virtual sharp
+	s = NewSketch()
+	l1 := s.AddLine(1.1, 2.1, 5, 2.2)
+	l2 := s.AddLine(1.1, 2.1, 1.2, 5.9)
+	s.AddCoincidentConstraint(l1.Start(), l2.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddVerticalConstraint(l2)
+	s.AddDistanceConstraint(l1, nil, 4)
+	s.AddDistanceConstraint(l2, nil, 4)
+	_, err = s.AddFillet(l1, l2, 1)
+	assert.Nil(t, err, "Expected fillet to be added")
+	sharp, err := s.AddIntersectionPoint(l1, l2, 0)
+	assert.Nil(t, err, "Expected intersection point to be added")
+	s.AddDistanceConstraint(sharp, s.XAxis, 2)
+	s.AddDistanceConstraint(sharp, s.YAxis, 1)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{1, 2}, sharp.Values(), utils.StandardCompare, "Sharp is at the corner of the lines")
+	assert.InDeltaSlice(t, []float64{2, 2, 5, 2}, l1.Values(), utils.StandardCompare, "First line runs from the fillet to its dimensioned end")
+
+	// The intersection stays on its branch when the circle moves past it
+	s = NewSketch()
+	l = s.AddLine(-20, 3, 20, 3)
+	c = s.AddCircle(10, 0, 5)
+	s.AddHorizontalConstraint(l)
+	s.AddDistanceConstraint(l, s.XAxis, 3)
+	s.AddDistanceConstraint(l.Start(), s.YAxis, 20)
+	s.AddDistanceConstraint(l, nil, 40)
+	s.AddCoincidentConstraint(c.Center(), s.XAxis)
+	s.AddDistanceConstraint(c, nil, 5)
+	s.SetParameter("x", 10)
+	s.AddDistanceExpression(c.Center(), s.YAxis, "x")
+	p, err = s.AddIntersectionPoint(l, c, 0)
+	assert.Nil(t, err, "Expected intersection point to be added")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{6, 3}, p.Values(), utils.StandardCompare, "Intersection is nearer the line's start")
+	assert.Nil(t, s.SetParameter("x", 3), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{-1, 3}, p.Values(), utils.StandardCompare, "Intersection stays nearer the line's start")
+}
+
+func TestSolvePatterns(t *testing.T) {
+	// A bolt circle follows its seed hole and angle
+	s := NewSketch()
+	hole := s.AddCircle(10.2, 0.3, 1.2)
+	s.SetParameter("radius", 1)
+	s.SetParameter("angle", 60)
+	s.AddDistanceExpression(hole, nil, "radius")
+	s.AddCoincidentConstraint(hole.Center(), s.XAxis)
+	s.AddDistanceConstraint(hole.Center(), s.Origin, 10)
+	copies, err := s.CircularPatternExpression([]*Element{hole}, 6, s.Origin, "angle")
+	assert.Nil(t, err, "Expected pattern to be added")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	for i, instance := range copies {
+		angle := float64(i+1) * math.Pi / 3
+		assert.InDeltaSlice(t, []float64{10 * math.Cos(angle), 10 * math.Sin(angle), 1}, instance[0].Values(), utils.StandardCompare, "Holes are rotated from the seed")
+	}
+	assert.Nil(t, s.SetParameter("angle", 45), "Expected successful solve")
+	assert.Nil(t, s.SetParameter("radius", 2), "Expected successful solve")
+	for i, instance := range copies {
+		angle := float64(i+1) * math.Pi / 4
+		assert.InDeltaSlice(t, []float64{10 * math.Cos(angle), 10 * math.Sin(angle), 2}, instance[0].Values(), utils.StandardCompare, "Holes follow the seed and angle")
+	}
+
+	// A rectangle patterned along a line follows its width and the spacing
+	s = NewSketch()
+	l1 := s.AddLine(0.1, 0, 2, 0.1)
+	l2 := s.AddLine(2, 0.1, 2.1, 1)
+	l3 := s.AddLine(2.1, 1, 0, 1.1)
+	l4 := s.AddLine(0, 1.1, 0.1, 0)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddCoincidentConstraint(l2.End(), l3.Start())
+	s.AddCoincidentConstraint(l3.End(), l4.Start())
+	s.AddCoincidentConstraint(l4.End(), l1.Start())
+	s.AddCoincidentConstraint(s.Origin, l1.Start())
+	s.AddHorizontalConstraint(l1)
+	s.AddHorizontalConstraint(l3)
+	s.AddVerticalConstraint(l2)
+	s.AddVerticalConstraint(l4)
+	s.SetParameter("width", 2)
+	s.SetParameter("gap", 3)
+	s.AddDistanceExpression(l1, nil, "width")
+	s.AddDistanceConstraint(l2, nil, 1)
+	direction := s.AddLine(0.1, -1.1, 1.1, -1.9)
+	s.AddCoincidentConstraint(direction.Start(), s.YAxis)
+	s.AddDistanceConstraint(direction.Start(), s.XAxis, 1)
+	s.AddAngleConstraint(s.XAxis, direction, -45, false)
+	s.AddDistanceConstraint(direction, nil, 1)
+	copies, err = s.LinearPatternExpression([]*Element{l1, l2, l3, l4}, 3, direction, "gap")
+	assert.Nil(t, err, "Expected pattern to be added")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	step := 3 / math.Sqrt2
+	for i, instance := range copies {
+		offset := step * float64(i+1)
+		assert.InDeltaSlice(t, []float64{offset, -offset, 2 + offset, -offset}, instance[0].Values(), utils.StandardCompare, "Rectangles are moved along the direction")
+		assert.InDeltaSlice(t, []float64{2 + offset, 1 - offset, offset, 1 - offset}, instance[2].Values(), utils.StandardCompare, "Rectangles are moved along the direction")
+	}
+	assert.Nil(t, s.SetParameter("width", 1), "Expected successful solve")
+	assert.Nil(t, s.SetParameter("gap", 1.5), "Expected successful solve")
+	step = 1.5 / math.Sqrt2
+	for i, instance := range copies {
+		offset := step * float64(i+1)
+		assert.InDeltaSlice(t, []float64{offset, -offset, 1 + offset, -offset}, instance[0].Values(), utils.StandardCompare, "Rectangles follow the seed and spacing")
+	}
+
+	// Lines and arcs sharing points are rotated together
+	s = NewSketch()
+	l1 = s.AddLine(2.1, 0.1, 4, 0.2)
+	l2 = s.AddLine(4, 0.2, 3.1, 1.1)
+	arc := s.AddArc(3.1, -0.9, 2.1, 0.1, 4, 0.2)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddCoincidentConstraint(arc.Start(), l1.Start())
+	s.AddCoincidentConstraint(arc.End(), l1.End())
+	s.AddCoincidentConstraint(l1.Start(), s.XAxis)
+	s.AddDistanceConstraint(l1.Start(), s.YAxis, 2)
+	s.AddHorizontalConstraint(l1)
+	s.AddDistanceConstraint(l1, nil, 2)
+	s.AddDistanceConstraint(l2, nil, 1.5)
+	s.AddAngleConstraint(l1, l2, 135, false)
+	s.AddDistanceConstraint(arc, nil, math.Sqrt2)
+	copies, err = s.CircularPattern([]*Element{l1, l2, arc}, 4, s.Origin, 90)
+	assert.Nil(t, err, "Expected pattern to be added")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 2, 0, 4}, copies[0][0].Values(), utils.StandardCompare, "Lines are rotated about the center")
+	assert.InDeltaSlice(t, []float64{0, 4, -1.5 / math.Sqrt2, 4 - (1.5 / math.Sqrt2)}, copies[0][1].Values(), utils.StandardCompare, "Lines are rotated about the center")
+	assert.InDeltaSlice(t, []float64{-3, 1, -2, 0, -4, 0}, copies[1][2].Values(), utils.StandardCompare, "Arcs are rotated about the center")
+	assert.InDeltaSlice(t, []float64{-1, -3, 0, -2, 0, -4}, copies[2][2].Values(), utils.StandardCompare, "Arcs are rotated about the center")
+}
+
+func TestSolveMirror(t *testing.T) {
+	// A mirrored profile follows its original
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 3.1, 0.1)
+	l2 := s.AddLine(3.1, 0.1, 0.1, 2.1)
+	s.AddCoincidentConstraint(l1.End(), l2.Start())
+	s.AddCoincidentConstraint(l1.Start(), s.Origin)
+	s.AddHorizontalConstraint(l1)
+	s.SetParameter("width", 3)
+	s.AddDistanceExpression(l1, nil, "width")
+	s.AddCoincidentConstraint(l2.End(), s.YAxis)
+	s.AddDistanceConstraint(l2.End(), s.Origin, 2)
+	copies, err := s.Mirror([]*Element{l1, l2}, s.YAxis)
+	assert.Nil(t, err, "Expected mirror to be added")
+	assert.Equal(t, 2, len(copies), "Expected mirror to be added")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, -3, 0}, copies[0].Values(), utils.StandardCompare, "Lines are mirrored")
+	assert.InDeltaSlice(t, []float64{-3, 0, 0, 2}, copies[1].Values(), utils.StandardCompare, "Lines are mirrored")
+	assert.Nil(t, s.SetParameter("width", 4), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, -4, 0}, copies[0].Values(), utils.StandardCompare, "Lines follow the original")
+	assert.InDeltaSlice(t, []float64{-4, 0, 0, 2}, copies[1].Values(), utils.StandardCompare, "Lines follow the original")
+
+	// Arcs and circles are mirrored across a line
+	s = NewSketch()
+	axis := s.AddLine(0, 0, 1, 1.1)
+	s.AddCoincidentConstraint(axis.Start(), s.Origin)
+	s.AddAngleConstraint(s.XAxis, axis, 45, false)
+	s.AddDistanceConstraint(axis, nil, math.Sqrt2)
+	arc := s.AddArc(2, 0, 3, 0, 1, 0.1)
+	s.AddCoincidentConstraint(arc.Center(), s.XAxis)
+	s.AddDistanceConstraint(arc.Center(), s.YAxis, 2)
+	s.AddDistanceConstraint(arc, nil, 1)
+	s.AddCoincidentConstraint(arc.Start(), s.XAxis)
+	s.AddCoincidentConstraint(arc.End(), s.XAxis)
+	circle := s.AddCircle(3, 2, 0.6)
+	s.AddDistanceConstraint(circle.Center(), s.XAxis, 2)
+	s.AddDistanceConstraint(circle.Center(), s.YAxis, 3)
+	s.AddDistanceConstraint(circle, nil, 0.5)
+	copies, err = s.Mirror([]*Element{arc, circle}, axis)
+	assert.Nil(t, err, "Expected mirror to be added")
+	assert.Equal(t, 2, len(copies), "Expected mirror to be added")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 2, 0, 1, 0, 3}, copies[0].Values(), utils.StandardCompare, "Arcs are mirrored and reversed")
+	assert.InDeltaSlice(t, []float64{2, 3, 0.5}, copies[1].Values(), utils.StandardCompare, "Circles are mirrored")
+
+	// A symmetric point starting on the same side of the line isn't solved onto its mirror
+	s = NewSketch()
+	p1 := s.AddPoint(2.1, 0.9)
+	p2 := s.AddPoint(1, 1.1)
+	s.AddDistanceConstraint(p1, s.YAxis, 2)
+	s.AddDistanceConstraint(p1, s.XAxis, 1)
+	symmetric, _ := s.AddSymmetricConstraint(p1, p2, s.YAxis)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.Equal(t, Solved, symmetric.state, "Symmetric constraint is solved")
+	assert.InDeltaSlice(t, []float64{2, 1}, p1.Values(), utils.StandardCompare, "Point is placed")
+	assert.InDeltaSlice(t, []float64{-2, 1}, p2.Values(), utils.StandardCompare, "Point is mirrored rather than on the placed point")
+
+	// Nor onto the other point its distance from the placed point finds on the same side
+	s = NewSketch()
+	line := s.AddLine(0.1, -0.1, 0.1, 2.1)
+	s.AddCoincidentConstraint(line.Start(), s.Origin)
+	s.AddCoincidentConstraint(line.End(), s.YAxis)
+	s.AddDistanceConstraint(line, nil, 2)
+	p1 = s.AddPoint(2.1, 0.9)
+	p2 = s.AddPoint(6.1, 1.1)
+	s.AddDistanceConstraint(p1, s.YAxis, 2)
+	s.AddDistanceConstraint(p1, s.XAxis, 1)
+	symmetric, _ = s.AddSymmetricConstraint(p1, p2, line)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.Equal(t, Solved, symmetric.state, "Symmetric constraint is solved")
+	assert.InDeltaSlice(t, []float64{-2, 1}, p2.Values(), utils.StandardCompare, "Point is mirrored across the line")
+}
+
+func TestSolveShapes(t *testing.T) {
+	// Rectangles are placed by a corner and sized by their sides
+	s := NewSketch()
+	sides, _ := s.AddCenterRectangle(2.1, 1.2, 4, 2.1)
+	s.AddCoincidentConstraint(s.Origin, sides[0].Start())
+	s.AddDistanceConstraint(sides[0], nil, 4)
+	s.SetParameter("height", 2)
+	s.AddDistanceExpression(sides[1], nil, "height")
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{4, 2, 0, 2}, sides[2].Values(), utils.StandardCompare, "Rectangle is sized by its sides")
+	assert.InDeltaSlice(t, []float64{2, 1}, sides[6].Values(), utils.StandardCompare, "Center follows the rectangle")
+	assert.Nil(t, s.SetParameter("height", 3), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{4, 3, 0, 3}, sides[2].Values(), utils.StandardCompare, "Rectangle is sized by its sides")
+	assert.InDeltaSlice(t, []float64{2, 1.5}, sides[6].Values(), utils.StandardCompare, "Center follows the rectangle")
+
+	// Regular polygons are placed by a corner and sized by one side
+	s = NewSketch()
+	sides, _ = s.AddRegularPolygon(2.1, 1.2, 2, 6)
+	s.AddCoincidentConstraint(s.Origin, sides[0].Start())
+	s.AddParallelConstraint(s.XAxis, sides[0])
+	s.SetParameter("side", 2)
+	s.AddDistanceExpression(sides[0], nil, "side")
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{2, 2 * math.Sqrt(3), 0, 2 * math.Sqrt(3)}, sides[3].Values(), utils.StandardCompare, "Sides are equal")
+	assert.InDeltaSlice(t, []float64{1, math.Sqrt(3)}, sides[6].Values(), utils.StandardCompare, "Center follows the polygon")
+	assert.Nil(t, s.SetParameter("side", 4), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{4, 4 * math.Sqrt(3), 0, 4 * math.Sqrt(3)}, sides[3].Values(), utils.StandardCompare, "Sides are equal")
+	assert.InDeltaSlice(t, []float64{2, 2 * math.Sqrt(3)}, sides[6].Values(), utils.StandardCompare, "Center follows the polygon")
+
+	// Regular polygons are placed by their center and sized by the distance to a corner
+	s = NewSketch()
+	sides, _ = s.AddRegularPolygon(0.1, 0.2, 2.2, 6)
+	s.AddCoincidentConstraint(s.Origin, sides[6])
+	s.AddDistanceConstraint(sides[6], sides[0].Start(), 2)
+	s.AddHorizontalConstraint(sides[0])
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	for _, c := range s.constraints {
+		assert.Equal(t, Solved, c.state, "Constraint %s is solved", c.constraintType)
+	}
+	assert.InDeltaSlice(t, []float64{-1, -math.Sqrt(3), 1, -math.Sqrt(3)}, sides[0].Values(), utils.StandardCompare, "Polygon is around its center")
+	assert.InDeltaSlice(t, []float64{1, math.Sqrt(3), -1, math.Sqrt(3)}, sides[3].Values(), utils.StandardCompare, "Polygon is sized by its radius")
+	assert.InDeltaSlice(t, []float64{0, 0}, sides[6].Values(), utils.StandardCompare, "Center is placed")
+
+	// Slots follow their centers
+	s = NewSketch()
+	c1 := s.AddPoint(0.1, 0.1)
+	c2 := s.AddPoint(3, 0.5)
+	s.AddCoincidentConstraint(s.Origin, c1)
+	s.AddCoincidentConstraint(c2, s.XAxis)
+	s.SetParameter("length", 4)
+	s.AddDistanceExpression(c1, c2, "length")
+	sides, _ = s.AddSlot(c1, c2, 1.5)
+	radius, _ := s.findConstraint(Distance, sides[3])
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.Equal(t, Solved, radius.state, "Slot radius is solved")
+	assert.InDeltaSlice(t, []float64{0, -1.5, 4, -1.5}, sides[0].Values(), utils.StandardCompare, "Lines are tangent to the arcs")
+	assert.InDeltaSlice(t, []float64{4, 0, 4, 1.5, 4, -1.5}, sides[1].Values(), utils.StandardCompare, "Arcs are around the centers")
+	assert.InDeltaSlice(t, []float64{4, 1.5, 0, 1.5}, sides[2].Values(), utils.StandardCompare, "Lines are tangent to the arcs")
+	assert.InDeltaSlice(t, []float64{0, 0, 0, -1.5, 0, 1.5}, sides[3].Values(), utils.StandardCompare, "Arcs have the dimensioned radius")
+	assert.Nil(t, s.SetParameter("length", 5), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, -1.5, 5, -1.5}, sides[0].Values(), utils.StandardCompare, "Lines follow the centers")
+	assert.InDeltaSlice(t, []float64{5, 0, 5, 1.5, 5, -1.5}, sides[1].Values(), utils.StandardCompare, "Arcs follow the centers")
+
+	// Arcs and circles through points follow the points
+	s = NewSketch()
+	p1 := s.AddPoint(1.1, 0.1)
+	p2 := s.AddPoint(0.1, 1.2)
+	p3 := s.AddPoint(-0.9, 0.1)
+	s.AddCoincidentConstraint(p1, s.XAxis)
+	s.AddDistanceConstraint(p1, s.YAxis, 1)
+	s.AddCoincidentConstraint(p2, s.YAxis)
+	s.SetParameter("height", 1)
+	s.AddDistanceExpression(p2, s.XAxis, "height")
+	s.AddCoincidentConstraint(p3, s.XAxis)
+	s.AddDistanceConstraint(p3, s.YAxis, 1)
+	arc, _ := s.AddArcThroughPoints(p1, p2, p3)
+	circle, _ := s.AddCircleThroughPoints(p3, p2, p1)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, -1, 0, 1, 0}, arc.Values(), utils.StandardCompare, "Arc passes through the points")
+	assert.InDeltaSlice(t, []float64{0, 0, 1}, circle.Values(), utils.StandardCompare, "Circle passes through the points")
+	assert.Nil(t, s.SetParameter("height", 2), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0.75, -1, 0, 1, 0}, arc.Values(), utils.StandardCompare, "Arc follows the points")
+	assert.InDeltaSlice(t, []float64{0, 0.75, 1.25}, circle.Values(), utils.StandardCompare, "Circle follows the points")
+
+	// Circles through points have a radius other constraints can use
+	s = NewSketch()
+	p1 = s.AddPoint(1.1, 0.1)
+	p2 = s.AddPoint(0.1, 1.2)
+	p3 = s.AddPoint(-0.9, 0.1)
+	s.AddCoincidentConstraint(p1, s.XAxis)
+	s.AddDistanceConstraint(p1, s.YAxis, 1)
+	s.AddCoincidentConstraint(p2, s.YAxis)
+	s.AddDistanceConstraint(p2, s.XAxis, 1)
+	s.AddCoincidentConstraint(p3, s.XAxis)
+	s.AddDistanceConstraint(p3, s.YAxis, 1)
+	circle, _ = s.AddCircleThroughPoints(p3, p2, p1)
+	l := s.AddLine(0.1, 1.6, 2.2, 1.5)
+	s.AddCoincidentConstraint(l.Start(), s.YAxis)
+	s.AddHorizontalConstraint(l)
+	s.AddDistanceConstraint(l, nil, 2)
+	tangent, _ := s.AddTangentConstraint(l, circle)
+	q := s.AddPoint(0.1, -1.1)
+	s.AddCoincidentConstraint(q, s.YAxis)
+	s.AddCoincidentConstraint(q, circle)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.Equal(t, Solved, tangent.state, "Tangent to a circle through points is solved")
+	assert.InDeltaSlice(t, []float64{0, 0, 1}, circle.Values(), utils.StandardCompare, "Circle passes through the points")
+	// The line may be tangent above or below the circle
+	values := l.Values()
+	assert.InDeltaSlice(t, []float64{0, 2}, []float64{values[0], values[2]}, utils.StandardCompare, "Line starts on the Y axis")
+	assert.InDelta(t, values[1], values[3], utils.StandardCompare, "Line is horizontal")
+	assert.InDelta(t, 1, math.Abs(values[1]), utils.StandardCompare, "Line is tangent to the circle")
+	assert.InDeltaSlice(t, []float64{0, -1}, q.Values(), utils.StandardCompare, "Point is on the circle")
+}
+
+func TestSolveBlocks(t *testing.T) {
+	// A tab with a hole
+	src := NewSketch()
+	sides, _ := src.AddRectangle(0.1, 0.1, 4, 2)
+	src.AddCoincidentConstraint(src.Origin, sides[0].Start())
+	src.SetParameter("width", 4)
+	src.AddDistanceExpression(sides[0], nil, "width")
+	src.AddDistanceConstraint(sides[1], nil, 2)
+	hole := src.AddCircle(2, 1, 0.5)
+	src.AddDistanceConstraint(hole.Center(), src.XAxis, 1)
+	src.AddDistanceConstraint(hole.Center(), src.YAxis, 2)
+	src.AddDistanceConstraint(hole, nil, 0.5)
+	assert.Nil(t, src.Solve(), "Expected successful solve")
+	b, err := src.NewBlock(nil, src.Origin, src.XAxis)
+	assert.Nil(t, err, "Expected block to be created")
+
+	// Instances are placed by their base and axis
+	s := NewSketch()
+	rigid, _ := s.InsertBlock(b, 0.2, 0.1, 5, false)
+	s.AddCoincidentConstraint(s.Origin, rigid.Base())
+	s.AddAngleConstraint(s.XAxis, rigid.Axis(), 0, false)
+	parametric, _ := s.InsertBlock(b, 10, 1, 80, true)
+	s.AddCoincidentConstraint(parametric.Base(), s.XAxis)
+	s.AddDistanceConstraint(parametric.Base(), s.YAxis, 10)
+	s.AddAngleConstraint(s.XAxis, parametric.Axis(), 90, false)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{4, 2, 0, 2}, rigid.Elements()[2].Values(), utils.StandardCompare, "Instance keeps the block's shape")
+	assert.InDeltaSlice(t, []float64{2, 1, 0.5}, rigid.Elements()[4].Values(), utils.StandardCompare, "Instance keeps the block's shape")
+	assert.InDeltaSlice(t, []float64{10, 4, 8, 4}, parametric.Elements()[1].Values(), utils.StandardCompare, "Instance is rotated by its axis")
+	assert.InDeltaSlice(t, []float64{9, 2, 0.5}, parametric.Elements()[4].Values(), utils.StandardCompare, "Instance is rotated by its axis")
+
+	// Parametric instances follow the block
+	assert.Nil(t, src.SetParameter("width", 6), "Expected successful solve")
+	assert.Nil(t, s.UpdateBlock(parametric), "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{10, 6, 8, 6}, parametric.Elements()[1].Values(), utils.StandardCompare, "Parametric instance follows the block")
+	assert.InDeltaSlice(t, []float64{9, 2, 0.5}, parametric.Elements()[4].Values(), utils.StandardCompare, "Parametric instance follows the block")
+	assert.InDeltaSlice(t, []float64{4, 2, 0, 2}, rigid.Elements()[2].Values(), utils.StandardCompare, "Rigid instance keeps its shape")
+
+	// Instances turned a quarter turn are rotated rather than mirrored
+	s = NewSketch()
+	turned, _ := s.InsertBlock(b, 0.1, 0.1, 0, false)
+	s.AddCoincidentConstraint(s.Origin, turned.Base())
+	s.AddAngleConstraint(s.XAxis, turned.Axis(), 90, false)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 6, -2, 6}, turned.Elements()[1].Values(), utils.StandardCompare, "Instance is rotated by its axis")
+	assert.InDeltaSlice(t, []float64{-1, 2, 0.5}, turned.Elements()[4].Values(), utils.StandardCompare, "Instance is rotated by its axis")
+}
+
+func TestSolveResolvedInLastPass(t *testing.T) {
+	// The length of l1 is only known once it is solved, so the equal constraint is resolved after the first
+	// pass and the sketch must be solved again even though nothing is left unresolved
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 3.1, 0.1)
+	s.AddCoincidentConstraint(l1.Start(), s.Origin)
+	s.AddCoincidentConstraint(l1.End(), s.XAxis)
+	s.AddDistanceConstraint(l1.End(), s.YAxis, 3)
+	l2 := s.AddLine(0, 0, 0.1, 2)
+	s.AddCoincidentConstraint(l2.Start(), s.Origin)
+	s.AddCoincidentConstraint(l2.End(), s.YAxis)
+	s.AddEqualConstraint(l1, l2)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 0, 3, 0}, l1.Values(), utils.StandardCompare, "Line is placed")
+	assert.InDeltaSlice(t, []float64{0, 0, 0, 3}, l2.Values(), utils.StandardCompare, "Equal line is solved in a later pass")
+}
+
+func TestSolveLengthFromSolvedPoints(t *testing.T) {
+	// l1 has no length dimension, so its length is measured once its end points are solved. The sketch's
+	// elements still hold the drawn points until the solve finishes, which would give l2 the drawn length.
+	s := NewSketch()
+	l1 := s.AddLine(0, 0, 1, 1)
+	s.AddCoincidentConstraint(l1.Start(), s.Origin)
+	s.AddDistanceConstraint(l1.End(), s.YAxis, 3)
+	s.AddDistanceConstraint(l1.End(), s.XAxis, 4)
+	l2 := s.AddLine(0, -1, 1, -1)
+	s.AddCoincidentConstraint(l2.Start(), s.YAxis)
+	s.AddDistanceConstraint(l2.Start(), s.XAxis, 1)
+	s.AddHorizontalConstraint(l2)
+	ratio := s.AddRatioConstraint(l1, l2, 2)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.Equal(t, Solved, ratio.state, "Ratio is solved")
+	assert.InDeltaSlice(t, []float64{0, 0, 3, 4}, l1.Values(), utils.StandardCompare, "Line is placed by its end points")
+	assert.InDeltaSlice(t, []float64{0, -1, 10, -1}, l2.Values(), utils.StandardCompare, "Length is measured from the solved points")
+}
+
+func TestSolveEqualArcs(t *testing.T) {
+	// Equal constraints give arcs the same length
+	s := NewSketch()
+	arc := s.AddArc(0.1, 0.1, 2.1, 0.2, 0.2, -1.9)
+	s.AddCoincidentConstraint(arc.Center(), s.Origin)
+	s.AddDistanceConstraint(arc, nil, 2)
+	s.AddCoincidentConstraint(arc.Start(), s.XAxis)
+	s.AddArcAngleConstraint(arc, 90)
+	other := s.AddArc(5.1, 0.1, 9.1, 0.2, 7.9, -2.9)
+	s.AddCoincidentConstraint(other.Center(), s.XAxis)
+	s.AddDistanceConstraint(other.Center(), s.YAxis, 5)
+	s.AddDistanceConstraint(other, nil, 4)
+	s.AddCoincidentConstraint(other.Start(), s.XAxis)
+	s.AddEqualConstraint(arc, other)
+
+	err := s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	sweep, _ := other.arcSweep()
+	assert.InDelta(t, math.Pi/4, sweep, utils.StandardCompare, "Arc of twice the radius has half the sweep")
+
+	// Equal constraints give a line the length along an arc
+	s = NewSketch()
+	arc = s.AddArc(0.1, 0.1, 2.1, 0.2, 0.2, -1.9)
+	s.AddCoincidentConstraint(arc.Center(), s.Origin)
+	s.AddDistanceConstraint(arc, nil, 2)
+	s.AddCoincidentConstraint(arc.Start(), s.XAxis)
+	s.AddArcAngleConstraint(arc, 90)
+	line := s.AddLine(0, 1, 3, 1.1)
+	s.AddCoincidentConstraint(line.Start(), s.YAxis)
+	s.AddDistanceConstraint(line.Start(), s.XAxis, 1)
+	s.AddHorizontalConstraint(line)
+	s.AddEqualConstraint(arc, line)
+
+	err = s.Solve()
+	assert.Nil(t, err, "Expected successful solve")
+	assert.InDeltaSlice(t, []float64{0, 1, math.Pi, 1}, line.Values(), utils.StandardCompare, "Line is as long as the arc")
+}
@@ -0,0 +1,113 @@
+package dlineate
+
+import (
+	"errors"
+	"math"
+
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func SymmetricConstraint(p1 *Element, p2 *Element, line *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, p1)
+	constraint.elements = append(constraint.elements, p2)
+	constraint.elements = append(constraint.elements, line)
+	constraint.constraintType = Symmetric
+	constraint.state = Unresolved
+
+	return constraint
+}
+
+// AddSymmetricConstraint adds a constraint keeping the points p1 and p2 mirrored across a line or axis.
+// The line between the points is kept perpendicular to line, and once either point is placed the other is
+// constrained to the same distance from line on its other side. A point placed on line is its own mirror, so
+// the other point is constrained onto line.
+func (s *Sketch) AddSymmetricConstraint(p1 *Element, p2 *Element, line *Element) (*Constraint, error) {
+	if p1 == nil || p2 == nil || line == nil || p1.elementType != Point || p2.elementType != Point ||
+		(line.elementType != Line && line.elementType != Axis) {
+		return nil, errors.New("incorrect element types for symmetric constraint")
+	}
+	if p1.id == p2.id || utils.StandardFloatCompare(math.Hypot(p2.values[0]-p1.values[0], p2.values[1]-p1.values[1]), 0) == 0 {
+		return nil, errors.New("symmetric points must be distinct")
+	}
+	if _, err := s.AddPerpendicularConstraint(s.addHelperLine(p1, p2), line); err != nil {
+		return nil, err
+	}
+
+	c := SymmetricConstraint(p1, p2, line)
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+	s.eToC[line.id] = append(s.eToC[line.id], c)
+	s.constraints = append(s.constraints, c)
+
+	s.resolveSymmetricConstraint(c)
+
+	return c, nil
+}
+
+func (s *Sketch) resolveSymmetricConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+
+	/*
+	 * The points are already on a line perpendicular to the mirror line, so once the mirror line and
+	 * either point are placed, the other point is the placed point's distance from the mirror line.
+	 * That distance has a solution on each side, so a side constraint keeps the other point opposite
+	 * the placed point rather than on it.
+	 */
+	p1 := c.elements[0]
+	p2 := c.elements[1]
+	line := c.elements[2]
+	if line.elementType == Line && !(s.isPointPlaced(line.children[0]) && s.isPointPlaced(line.children[1])) {
+		return false
+	}
+
+	var source, target *Element
+	switch {
+	case s.isPointPlaced(p1):
+		source, target = p1, p2
+	case s.isPointPlaced(p2):
+		source, target = p2, p1
+	default:
+		return false
+	}
+
+	currentLine, ok := s.sketch.GetElement(line.element.GetID())
+	if !ok {
+		currentLine = line.element
+	}
+	// Solved lines aren't kept normalized
+	mirror := el.CopySketchElement(currentLine).AsLine()
+	mirror.Normalize()
+	current := s.currentPoint(source)
+	x, _ := current.GetX().Float64()
+	y, _ := current.GetY().Float64()
+	a, _ := mirror.GetA().Float64()
+	b, _ := mirror.GetB().Float64()
+	offset, _ := mirror.GetC().Float64()
+	offset += (a * x) + (b * y)
+	dist := math.Abs(offset)
+	// The graph solver picks the solution nearest the point's current position, so start it at the mirror
+	if !s.isPointPlaced(target) {
+		el.SetElementValues(s.currentPoint(target), []float64{x - (2 * a * offset), y - (2 * b * offset)})
+	}
+	constraint := s.addDistanceConstraint(target, line, dist)
+	if constraint != nil {
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("type", c.constraintType.String()).
+			Msg("resolveSymmetricConstraint: added constraint")
+		target.constraints = append(target.constraints, constraint)
+		line.constraints = append(line.constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	// A point on the mirror line is its own mirror, so there is no side to keep
+	if measure, points, side := s.lineSide(line, target, x, y); side != 0 {
+		s.addDerivedSide(c, measure, points, -side)
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
@@ -2,6 +2,8 @@ package dlineate
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 
 	"github.com/marcuswu/dlineate/utils"
 )
@@ -17,7 +19,33 @@ func TangentConstraint(p1 *Element, p2 *Element) *Constraint {
 	return constraint
 }
 
+// TangentMode selects how two tangent curves touch
+type TangentMode uint
+
+const (
+	// ExternalTangent curves touch from the outside -- their centers are r1 + r2 apart
+	ExternalTangent TangentMode = iota
+	// InternalTangent curves touch with one inside the other -- their centers are |r1 - r2| apart
+	InternalTangent
+)
+
+func (m TangentMode) String() string {
+	switch m {
+	case ExternalTangent:
+		return "External"
+	case InternalTangent:
+		return "Internal"
+	default:
+		return fmt.Sprintf("%d", int(m))
+	}
+}
+
+// AddTangentConstraint adds a constraint keeping a line tangent to a circle or arc.
+// Two circles or arcs are made externally tangent. Use AddCurveTangentConstraint to choose the mode.
 func (s *Sketch) AddTangentConstraint(p1 *Element, p2 *Element) (*Constraint, error) {
+	if p1.Center() != nil && p2.Center() != nil {
+		return s.AddCurveTangentConstraint(p1, p2, ExternalTangent)
+	}
 	var line, curve, err = orderParams(p1, p2)
 
 	if err != nil {
@@ -60,7 +88,29 @@ func orderParams(p1 *Element, p2 *Element) (*Element, *Element, error) {
 	return line, curve, nil
 }
 
+// AddCurveTangentConstraint adds a constraint keeping the circles or arcs p1 and p2 tangent to each other.
+// The constraint is resolved into a distance between the curve centers once both radii are known.
+func (s *Sketch) AddCurveTangentConstraint(p1 *Element, p2 *Element, mode TangentMode) (*Constraint, error) {
+	if p1.Center() == nil || p2.Center() == nil || p1 == p2 {
+		utils.Logger.Error().Msg("Tangent constraint had incorrect parameters")
+		return nil, errors.New("incorrect element types for curve tangent constraint")
+	}
+
+	c := TangentConstraint(p1, p2)
+	c.tangentMode = mode
+	s.eToC[p1.id] = append(s.eToC[p1.id], c)
+	s.eToC[p2.id] = append(s.eToC[p2.id], c)
+	s.constraints = append(s.constraints, c)
+
+	s.resolveTangentConstraint(c)
+
+	return c, nil
+}
+
 func (s *Sketch) resolveTangentConstraint(c *Constraint) bool {
+	if c.elements[0].Center() != nil {
+		return s.resolveCurveTangentConstraint(c)
+	}
 	radius, ok := s.resolveCurveRadius(c.elements[1])
 	if ok {
 		utils.Logger.Debug().
@@ -80,3 +130,43 @@ func (s *Sketch) resolveTangentConstraint(c *Constraint) bool {
 
 	return c.state == Resolved
 }
+
+func (s *Sketch) resolveCurveTangentConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+	r1, ok := s.resolveCurveRadius(c.elements[0])
+	if !ok {
+		return false
+	}
+	r2, ok := s.resolveCurveRadius(c.elements[1])
+	if !ok {
+		return false
+	}
+
+	var dist big.Float
+	dist.SetPrec(utils.FloatPrecision)
+	if c.tangentMode == InternalTangent {
+		dist.Sub(r1, r2)
+		dist.Abs(&dist)
+	} else {
+		dist.Add(r1, r2)
+	}
+	v, _ := dist.Float64()
+
+	c1 := c.elements[0].Center()
+	c2 := c.elements[1].Center()
+	constraint := s.addDistanceConstraint(c1, c2, v)
+	if constraint != nil {
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("mode", c.tangentMode.String()).
+			Msg("resolveTangentConstraint: added constraint")
+		c.elements[0].constraints = append(c.elements[0].constraints, constraint)
+		c.elements[1].constraints = append(c.elements[1].constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
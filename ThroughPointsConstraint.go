@@ -0,0 +1,78 @@
+package dlineate
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+func ThroughPointsConstraint(circle *Element, p1 *Element, p2 *Element, p3 *Element) *Constraint {
+	constraint := emptyConstraint()
+	constraint.elements = append(constraint.elements, circle, p1, p2, p3)
+	constraint.constraintType = ThroughPoints
+	constraint.state = Unresolved
+
+	return constraint
+}
+
+// addThroughPointsConstraint adds a constraint keeping the circle or arc through the points p1, p2 and p3
+func (s *Sketch) addThroughPointsConstraint(circle *Element, p1 *Element, p2 *Element, p3 *Element) *Constraint {
+	c := ThroughPointsConstraint(circle, p1, p2, p3)
+	s.constraints = append(s.constraints, c)
+	for _, e := range c.elements {
+		s.eToC[e.id] = append(s.eToC[e.id], c)
+	}
+
+	s.resolveThroughPointsConstraint(c)
+
+	return c
+}
+
+func (s *Sketch) resolveThroughPointsConstraint(c *Constraint) bool {
+	if c.state == Resolved || c.state == Solved {
+		return true
+	}
+
+	/*
+	 * Once the points are placed, the circle through them is known. Its center is the radius from the first
+	 * two points, which the third is then on. The center is moved to where the circle's center is first so
+	 * the solver picks it rather than its reflection across the line between the points.
+	 */
+	circle := c.elements[0]
+	points := c.elements[1:]
+	coords := make([]float64, 0, 2*len(points))
+	for _, p := range points {
+		if !s.isPointPlaced(p) {
+			return false
+		}
+		x, _ := s.currentPoint(p).GetX().Float64()
+		y, _ := s.currentPoint(p).GetY().Float64()
+		coords = append(coords, x, y)
+	}
+	cx, cy, ok := circleCenter(coords[0], coords[1], coords[2], coords[3], coords[4], coords[5])
+	if !ok {
+		return false
+	}
+	radius := math.Hypot(coords[0]-cx, coords[1]-cy)
+
+	center := circle.Center()
+	moved := s.currentPoint(center)
+	var dx, dy big.Float
+	dx.SetPrec(utils.FloatPrecision).Sub(big.NewFloat(cx), moved.GetX())
+	dy.SetPrec(utils.FloatPrecision).Sub(big.NewFloat(cy), moved.GetY())
+	moved.Translate(&dx, &dy)
+
+	for _, p := range points[:2] {
+		constraint := s.addDistanceConstraint(center, p, radius)
+		utils.Logger.Debug().
+			Uint("constraint", constraint.GetID()).
+			Str("type", c.constraintType.String()).
+			Msg("resolveThroughPointsConstraint: added constraint")
+		center.constraints = append(center.constraints, constraint)
+		c.constraints = append(c.constraints, constraint)
+	}
+	c.state = Resolved
+
+	return c.state == Resolved
+}
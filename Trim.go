@@ -0,0 +1,349 @@
+package dlineate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// Split breaks a line or arc into two at the point on it nearest [x, y].
+// The element keeps its start and ends at the split point. A new element of the same type runs from the
+// split point to the original end point, which keeps its constraints and now ends the new element.
+// Constraints on the element itself stay with the first piece, so a length dimension sets the length of the
+// first piece rather than both. The pieces remain joined at the split point
+// and the new piece stays on the same line or circle as the first, so the split follows the element when
+// the sketch is solved.
+// It returns the new element created.
+func (s *Sketch) Split(e *Element, x float64, y float64) (*Element, error) {
+	if e == nil || (e.elementType != Line && e.elementType != Arc) {
+		return nil, errors.New("incorrect element type for split")
+	}
+	curve, err := editCurve(e)
+	if err != nil {
+		return nil, err
+	}
+	px, py := curve.nearest(s.units.toLength(x), s.units.toLength(y))
+	position := curve.position(px, py)
+	if position <= utils.StandardCompare || position >= curve.length()-utils.StandardCompare {
+		return nil, errors.New("split point must be within the element")
+	}
+
+	originalEnd := e.End()
+	var piece *Element
+	if e.elementType == Line {
+		piece = s.addLine(px, py, e.values[2], e.values[3])
+	} else {
+		piece = s.addArc(e.values[0], e.values[1], px, py, e.values[4], e.values[5])
+	}
+	piece.SetConstruction(e.construction)
+	if e.elementType == Arc {
+		// The arcs share a center and the original arc keeps its end points on the circle
+		s.clearArcConstraints(piece)
+	}
+
+	end := s.replaceEndpoint(e, 1, px, py)
+	s.retargetConstraints(e, originalEnd)
+	s.AddCoincidentConstraint(piece.Start(), end)
+	s.AddCoincidentConstraint(piece.End(), originalEnd)
+	if e.elementType == Arc {
+		s.AddCoincidentConstraint(piece.Center(), e.Center())
+	}
+
+	utils.Logger.Info().
+		Uint("element", e.element.GetID()).
+		Uint("new element", piece.element.GetID()).
+		Float64("x", px).
+		Float64("y", py).
+		Msg("Split element")
+	return piece, nil
+}
+
+// Trim cuts a line or arc where it crosses the element at and removes the part of e nearest [x, y], between
+// the crossing and the end of e. The element at may be a point, line, axis, circle or arc. Where e crosses
+// at more than once, the part removed runs from the end of e picked to the nearest crossing, so [x, y] must
+// not be between two crossings.
+// The trimmed end is constrained coincident with at, so the trim follows it when the sketch is solved.
+// Constraints on e itself, such as its length, apply to the trimmed element. If the previous end point has
+// other constraints it remains in the sketch, constrained to the line or circle of e.
+// It returns the new end point of e.
+func (s *Sketch) Trim(e *Element, at *Element, x float64, y float64) (*Element, error) {
+	if e == nil || (e.elementType != Line && e.elementType != Arc) {
+		return nil, errors.New("incorrect element type for trim")
+	}
+	curve, err := editCurve(e)
+	if err != nil {
+		return nil, err
+	}
+	points, err := editIntersections(curve, at)
+	if err != nil {
+		return nil, err
+	}
+
+	length := curve.length()
+	pick := curve.position(curve.nearest(s.units.toLength(x), s.units.toLength(y)))
+	if !curve.line && pick > length && (2*math.Pi*curve.r)-pick < pick-length {
+		// Picks in the gap of an arc are nearer its start
+		pick = 0
+	}
+
+	found := false
+	var first, last [2]float64
+	var firstPosition, lastPosition float64
+	for _, p := range points {
+		if !curve.onCurve(p[0], p[1]) {
+			continue
+		}
+		position := curve.position(p[0], p[1])
+		// Crossings at an end leave nothing to trim
+		if position <= utils.StandardCompare || position >= length-utils.StandardCompare {
+			continue
+		}
+		if !found || position < firstPosition {
+			first, firstPosition = p, position
+		}
+		if !found || position > lastPosition {
+			last, lastPosition = p, position
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("element %d doesn't cross element %d", e.id, at.id)
+	}
+
+	var px, py float64
+	end := 0
+	switch {
+	case pick < firstPosition:
+		px, py = first[0], first[1]
+	case pick > lastPosition:
+		px, py, end = last[0], last[1], 1
+	default:
+		return nil, errors.New("trim pick point must be between an end of the element and a crossing")
+	}
+
+	previous := e.Start()
+	if end == 1 {
+		previous = e.End()
+	}
+	p := s.replaceEndpoint(e, end, px, py)
+	s.retargetConstraints(e, previous)
+	s.AddCoincidentConstraint(p, at)
+	s.releaseEndpoint(previous)
+
+	utils.Logger.Info().
+		Uint("element", e.element.GetID()).
+		Uint("at", at.element.GetID()).
+		Float64("x", px).
+		Float64("y", py).
+		Msg("Trimmed element")
+	return p, nil
+}
+
+// Extend lengthens a line to meet the element to, which may be a point, line, axis, circle or arc.
+// The end of the line nearer to where its extension meets to is moved there and constrained coincident
+// with to, so the extension follows it when the sketch is solved. Constraints on the line itself, such as
+// its length, apply to the extended line. If the previous end point has other constraints it remains in
+// the sketch, constrained to the line.
+// It returns the new end point of the line.
+func (s *Sketch) Extend(e *Element, to *Element) (*Element, error) {
+	if e == nil || e.elementType != Line {
+		return nil, errors.New("incorrect element type for extend")
+	}
+	curve, err := editCurve(e)
+	if err != nil {
+		return nil, err
+	}
+	points, err := editIntersections(curve, to)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	var x, y, nearest float64
+	end := 0
+	length := curve.length()
+	for _, p := range points {
+		position := curve.position(p[0], p[1])
+		distance, index := -position, 0
+		if position > length/2 {
+			distance, index = position-length, 1
+		}
+		// Only points beyond the ends of the line extend it
+		if distance <= utils.StandardCompare {
+			continue
+		}
+		if !found || distance < nearest {
+			found, x, y, nearest, end = true, p[0], p[1], distance, index
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("extending element %d doesn't meet element %d", e.id, to.id)
+	}
+
+	previous := e.children[end]
+	p := s.replaceLineEndpoint(e, end, x, y)
+	s.retargetConstraints(e, previous)
+	s.AddCoincidentConstraint(p, to)
+	s.releaseEndpoint(previous)
+
+	utils.Logger.Info().
+		Uint("element", e.element.GetID()).
+		Uint("to", to.element.GetID()).
+		Float64("x", x).
+		Float64("y", y).
+		Msg("Extended element")
+	return p, nil
+}
+
+// editCurve returns the curve of a line or arc being edited
+func editCurve(e *Element) (profileCurve, error) {
+	curve := newProfileCurve(e)
+	if curve.length() <= utils.StandardCompare {
+		return curve, errors.New("element must have a length")
+	}
+	return curve, nil
+}
+
+// editIntersections returns the points where the line or circle of a curve meets the element at
+func editIntersections(curve profileCurve, at *Element) ([][2]float64, error) {
+	if at == nil || at.id == curve.element.id {
+		return nil, errors.New("incorrect element types for intersection")
+	}
+	switch at.elementType {
+	case Point:
+		x, y := at.values[0], at.values[1]
+		nx, ny := curve.nearest(x, y)
+		if utils.StandardFloatCompare(math.Hypot(x-nx, y-ny), 0) != 0 {
+			return nil, nil
+		}
+		return [][2]float64{{x, y}}, nil
+	case Line, Axis, Circle, Arc:
+		other := newProfileCurve(at)
+		if other.line && other.length() <= utils.StandardCompare {
+			return nil, errors.New("element must have a length")
+		}
+		points := make([][2]float64, 0)
+		for _, p := range curve.crossings(other) {
+			if other.onCurve(p[0], p[1]) {
+				points = append(points, p)
+			}
+		}
+		return points, nil
+	}
+	return nil, errors.New("incorrect element types for intersection")
+}
+
+// nearest returns the point on the curve's line or circle nearest [x, y]
+func (c profileCurve) nearest(x float64, y float64) (float64, float64) {
+	if c.line {
+		ux, uy, _ := unitVector(c.x2-c.x1, c.y2-c.y1)
+		t := ((x - c.x1) * ux) + ((y - c.y1) * uy)
+		return c.x1 + (t * ux), c.y1 + (t * uy)
+	}
+	ux, uy, length := unitVector(x-c.cx, y-c.cy)
+	if length == 0 {
+		return c.x1, c.y1
+	}
+	return c.cx + (ux * c.r), c.cy + (uy * c.r)
+}
+
+// replaceEndpoint gives a line or arc a new start (index 0) or end (index 1) point at [x, y].
+// The previous endpoint remains in the sketch constrained to the line or circle.
+func (s *Sketch) replaceEndpoint(e *Element, index int, x float64, y float64) *Element {
+	if e.elementType == Line {
+		return s.replaceLineEndpoint(e, index, x, y)
+	}
+	return s.replaceArcEndpoint(e, index+1, x, y)
+}
+
+// replaceArcEndpoint gives an arc a new start (index 1) or end (index 2) point at [x, y].
+// The previous endpoint remains in the sketch constrained to the arc's circle.
+func (s *Sketch) replaceArcEndpoint(arc *Element, index int, x float64, y float64) *Element {
+	p := s.addPoint(x, y)
+	p.isChild = true
+	p.construction = arc.construction
+	arc.children[index] = p
+	arc.values[index*2] = x
+	arc.values[(index*2)+1] = y
+	s.addDistance(arc, p, 0.0)
+	return p
+}
+
+// retargetConstraints moves the constraints on a line or arc itself from its replaced end point previous to
+// its current end points. Lengths are added again between the new end points, length limits are measured
+// between them and derived constraints, such as arc angles, are resolved again.
+// Constraints on the previous end point stay with it.
+func (s *Sketch) retargetConstraints(e *Element, previous *Element) {
+	id := previous.element.GetID()
+	for _, c := range s.eToC[e.id] {
+		if slices.Contains(c.elements, previous) {
+			continue
+		}
+		if c.isInequality() {
+			// Line length limits are the only inequalities measured between a line's end points
+			if len(c.elements) == 1 {
+				c.inequality.Element1, c.inequality.Element2 = e.Start().element.GetID(), e.End().element.GetID()
+			}
+			continue
+		}
+		referenced := false
+		for _, constraint := range c.constraints {
+			referenced = referenced || constraint.HasElementID(id)
+		}
+		if !referenced {
+			continue
+		}
+		if c.isDerived() {
+			s.unresolveConstraint(c)
+			continue
+		}
+		if c.constraintType != Distance || len(c.elements) != 1 {
+			continue
+		}
+		for _, constraint := range c.constraints {
+			s.sketch.RemoveConstraint(constraint)
+			e.forgetConstraint(constraint)
+		}
+		c.constraints = c.constraints[:0]
+		c.state = Resolved
+		if constraint := s.addDistanceConstraint(e, nil, c.dataValue); constraint != nil {
+			e.constraints = append(e.constraints, constraint)
+			c.constraints = append(c.constraints, constraint)
+		}
+		utils.Logger.Debug().
+			Str("type", c.constraintType.String()).
+			Uint("element", e.element.GetID()).
+			Msg("Retargeted constraint to new end point")
+	}
+}
+
+// releaseEndpoint removes a replaced end point which was only held by its element, so trimming and
+// extending don't leave a point free to slide along the element
+func (s *Sketch) releaseEndpoint(previous *Element) {
+	constraints := s.eToC[previous.id]
+	if len(constraints) != 1 || constraints[0].constraintType != Distance {
+		return
+	}
+	c := constraints[0]
+	// Points merged with it by coincident constraints may still be referenced by other constraints
+	if previous.element.IsFixed() || len(s.sketch.ConstraintsForElement(previous.element.GetID())) != len(c.constraints) {
+		return
+	}
+	s.deleteConstraint(c)
+	previous.hidden = true
+}
+
+// deleteConstraint removes a constraint and its internal constraints from the sketch
+func (s *Sketch) deleteConstraint(c *Constraint) {
+	s.removeConstraint(c)
+	s.removeDerivedSides(c)
+	for _, constraint := range c.constraints {
+		s.sketch.RemoveConstraint(constraint)
+		for _, other := range c.elements {
+			other.forgetConstraint(constraint)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package dlineate
+
+import (
+	"fmt"
+	"math"
+)
+
+// LengthUnit is a unit of length used for sketch values
+type LengthUnit uint
+
+// LengthUnit constants
+const (
+	Millimeter LengthUnit = iota
+	Centimeter
+	Meter
+	Inch
+)
+
+func (u LengthUnit) String() string {
+	switch u {
+	case Millimeter:
+		return "mm"
+	case Centimeter:
+		return "cm"
+	case Meter:
+		return "m"
+	case Inch:
+		return "in"
+	default:
+		return fmt.Sprintf("%d", int(u))
+	}
+}
+
+// millimeters returns the number of millimeters in one unit
+func (u LengthUnit) millimeters() float64 {
+	switch u {
+	case Centimeter:
+		return 10
+	case Meter:
+		return 1000
+	case Inch:
+		return 25.4
+	}
+	return 1
+}
+
+// AngleUnit is a unit of angle used for sketch values
+type AngleUnit uint
+
+// AngleUnit constants
+const (
+	Degree AngleUnit = iota
+	Radian
+)
+
+func (u AngleUnit) String() string {
+	switch u {
+	case Degree:
+		return "deg"
+	case Radian:
+		return "rad"
+	default:
+		return fmt.Sprintf("%d", int(u))
+	}
+}
+
+// degrees returns the number of degrees in one unit
+func (u AngleUnit) degrees() float64 {
+	if u == Radian {
+		return 180 / math.Pi
+	}
+	return 1
+}
+
+// LengthValue is a length with its unit. Dimensions such as AddDistanceConstraintValue take it directly,
+// and Sketch.Length converts it for other values such as element coordinates.
+type LengthValue struct {
+	millimeters float64
+}
+
+// Millimeters returns a length of v millimeters
+func Millimeters(v float64) LengthValue {
+	return LengthValue{millimeters: v}
+}
+
+// Centimeters returns a length of v centimeters
+func Centimeters(v float64) LengthValue {
+	return LengthValue{millimeters: v * Centimeter.millimeters()}
+}
+
+// Meters returns a length of v meters
+func Meters(v float64) LengthValue {
+	return LengthValue{millimeters: v * Meter.millimeters()}
+}
+
+// Inches returns a length of v inches
+func Inches(v float64) LengthValue {
+	return LengthValue{millimeters: v * Inch.millimeters()}
+}
+
+// In returns the length in the unit u
+func (l LengthValue) In(u LengthUnit) float64 {
+	return l.millimeters / u.millimeters()
+}
+
+func (l LengthValue) String() string {
+	return fmt.Sprintf("%gmm", l.millimeters)
+}
+
+// AngleValue is an angle with its unit. Dimensions such as AddAngleConstraintValue take it directly,
+// and Sketch.Angle converts it for other values.
+type AngleValue struct {
+	degrees float64
+}
+
+// Degrees returns an angle of v degrees
+func Degrees(v float64) AngleValue {
+	return AngleValue{degrees: v}
+}
+
+// Radians returns an angle of v radians
+func Radians(v float64) AngleValue {
+	return AngleValue{degrees: v * Radian.degrees()}
+}
+
+// In returns the angle in the unit u
+func (a AngleValue) In(u AngleUnit) float64 {
+	return a.degrees / u.degrees()
+}
+
+func (a AngleValue) String() string {
+	return fmt.Sprintf("%gdeg", a.degrees)
+}
+
+// unitSystem holds the units a sketch's values are given and returned in.
+// Values are stored in millimeters and degrees within the sketch.
+type unitSystem struct {
+	length LengthUnit
+	angle  AngleUnit
+}
+
+// toLength converts a length in the sketch's units to millimeters
+func (u *unitSystem) toLength(v float64) float64 {
+	if u == nil {
+		return v
+	}
+	return v * u.length.millimeters()
+}
+
+// fromLength converts a length in millimeters to the sketch's units
+func (u *unitSystem) fromLength(v float64) float64 {
+	if u == nil {
+		return v
+	}
+	return v / u.length.millimeters()
+}
+
+// toAngle converts an angle in the sketch's units to degrees
+func (u *unitSystem) toAngle(v float64) float64 {
+	if u == nil {
+		return v
+	}
+	return v * u.angle.degrees()
+}
+
+// fromAngle converts an angle in degrees to the sketch's units
+func (u *unitSystem) fromAngle(v float64) float64 {
+	if u == nil {
+		return v
+	}
+	return v / u.angle.degrees()
+}
+
+// toCanonical converts a constraint value in the sketch's units to millimeters (squared for areas) or degrees
+func (u *unitSystem) toCanonical(ctype ConstraintType, v float64) float64 {
+	switch {
+	case ctype == Area:
+		return u.toLength(u.toLength(v))
+	case ctype.isLength():
+		return u.toLength(v)
+	case ctype.isAngle():
+		return u.toAngle(v)
+	}
+	return v
+}
+
+// fromCanonical converts a constraint value in millimeters (squared for areas) or degrees to the sketch's units
+func (u *unitSystem) fromCanonical(ctype ConstraintType, v float64) float64 {
+	switch {
+	case ctype == Area:
+		return u.fromLength(u.fromLength(v))
+	case ctype.isLength():
+		return u.fromLength(v)
+	case ctype.isAngle():
+		return u.fromAngle(v)
+	}
+	return v
+}
+
+// isLength returns whether values of the constraint type are lengths
+func (c ConstraintType) isLength() bool {
+	switch c {
+	case Distance, Coincident, HorizontalDistance, VerticalDistance, ArcLength, MinDistance, MaxDistance, Perimeter, ReferenceDistance:
+		return true
+	}
+	return false
+}
+
+// isAngle returns whether values of the constraint type are angles
+func (c ConstraintType) isAngle() bool {
+	switch c {
+	case Angle, Parallel, Perpendicular, ArcAngle, PointAngle, MinAngle, MaxAngle, ReferenceAngle:
+		return true
+	}
+	return false
+}
+
+// SetUnits sets the units used for the lengths and angles passed to the sketch's Add methods and returned by
+// Element.Values and Constraint.Value. The default units are millimeters and degrees.
+// Changing units doesn't change existing elements or constraints, only how their values are given and reported.
+func (s *Sketch) SetUnits(length LengthUnit, angle AngleUnit) {
+	s.units.length = length
+	s.units.angle = angle
+}
+
+// LengthUnit returns the unit used for lengths in the sketch
+func (s *Sketch) LengthUnit() LengthUnit {
+	return s.units.length
+}
+
+// AngleUnit returns the unit used for angles in the sketch
+func (s *Sketch) AngleUnit() AngleUnit {
+	return s.units.angle
+}
+
+// Length converts a length to the sketch's length unit, for example s.Length(Inches(2))
+func (s *Sketch) Length(l LengthValue) float64 {
+	return l.In(s.units.length)
+}
+
+// Angle converts an angle to the sketch's angle unit, for example s.Angle(Degrees(30))
+func (s *Sketch) Angle(a AngleValue) float64 {
+	return a.In(s.units.angle)
+}
+
+/*
+ * Typed dimensions
+ * These add the same constraints as the Add methods taking bare values, with lengths and angles in any unit,
+ * for example s.AddDistanceConstraintValue(l1, nil, Inches(2)) in a sketch using millimeters.
+ */
+
+// AddDistanceConstraintValue adds a distance constraint like AddDistanceConstraint with a length in any unit
+func (s *Sketch) AddDistanceConstraintValue(p1 *Element, p2 *Element, v LengthValue) *Constraint {
+	return s.AddDistanceConstraint(p1, p2, s.Length(v))
+}
+
+// AddHorizontalDistanceConstraintValue adds a horizontal distance constraint like
+// AddHorizontalDistanceConstraint with a length in any unit
+func (s *Sketch) AddHorizontalDistanceConstraintValue(p1 *Element, p2 *Element, v LengthValue) (*Constraint, error) {
+	return s.AddHorizontalDistanceConstraint(p1, p2, s.Length(v))
+}
+
+// AddVerticalDistanceConstraintValue adds a vertical distance constraint like AddVerticalDistanceConstraint
+// with a length in any unit
+func (s *Sketch) AddVerticalDistanceConstraintValue(p1 *Element, p2 *Element, v LengthValue) (*Constraint, error) {
+	return s.AddVerticalDistanceConstraint(p1, p2, s.Length(v))
+}
+
+// AddArcLengthConstraintValue adds an arc length constraint like AddArcLengthConstraint with a length in any unit
+func (s *Sketch) AddArcLengthConstraintValue(p1 *Element, v LengthValue) (*Constraint, error) {
+	return s.AddArcLengthConstraint(p1, s.Length(v))
+}
+
+// AddMinDistanceConstraintValue adds a minimum distance constraint like AddMinDistanceConstraint with a length
+// in any unit
+func (s *Sketch) AddMinDistanceConstraintValue(p1 *Element, p2 *Element, v LengthValue) (*Constraint, error) {
+	return s.AddMinDistanceConstraint(p1, p2, s.Length(v))
+}
+
+// AddMaxDistanceConstraintValue adds a maximum distance constraint like AddMaxDistanceConstraint with a length
+// in any unit
+func (s *Sketch) AddMaxDistanceConstraintValue(p1 *Element, p2 *Element, v LengthValue) (*Constraint, error) {
+	return s.AddMaxDistanceConstraint(p1, p2, s.Length(v))
+}
+
+// AddPerimeterConstraintValue adds a perimeter constraint like AddPerimeterConstraint with a length in any unit
+func (s *Sketch) AddPerimeterConstraintValue(loop []*Element, perimeter LengthValue) (*Constraint, error) {
+	return s.AddPerimeterConstraint(loop, s.Length(perimeter))
+}
+
+// AddAngleConstraintValue adds an angle constraint like AddAngleConstraint with an angle in any unit
+func (s *Sketch) AddAngleConstraintValue(p1 *Element, p2 *Element, v AngleValue, useSupplementary bool) (*Constraint, error) {
+	return s.AddAngleConstraint(p1, p2, s.Angle(v), useSupplementary)
+}
+
+// AddArcAngleConstraintValue adds an arc angle constraint like AddArcAngleConstraint with an angle in any unit
+func (s *Sketch) AddArcAngleConstraintValue(p1 *Element, v AngleValue) (*Constraint, error) {
+	return s.AddArcAngleConstraint(p1, s.Angle(v))
+}
+
+// AddPointAngleConstraintValue adds a point angle constraint like AddPointAngleConstraint with an angle in
+// any unit
+func (s *Sketch) AddPointAngleConstraintValue(p1 *Element, vertex *Element, p2 *Element, v AngleValue) (*Constraint, error) {
+	return s.AddPointAngleConstraint(p1, vertex, p2, s.Angle(v))
+}
+
+// AddMinAngleConstraintValue adds a minimum angle constraint like AddMinAngleConstraint with an angle in any unit
+func (s *Sketch) AddMinAngleConstraintValue(p1 *Element, p2 *Element, v AngleValue) (*Constraint, error) {
+	return s.AddMinAngleConstraint(p1, p2, s.Angle(v))
+}
+
+// AddMaxAngleConstraintValue adds a maximum angle constraint like AddMaxAngleConstraint with an angle in any unit
+func (s *Sketch) AddMaxAngleConstraintValue(p1 *Element, p2 *Element, v AngleValue) (*Constraint, error) {
+	return s.AddMaxAngleConstraint(p1, p2, s.Angle(v))
+}
@@ -39,3 +39,27 @@ func (v *Vector3D) UnitVector() (*Vector3D, bool) {
 	}
 	return &Vector3D{v.X / mag, v.Y / mag, v.Z / mag}, true
 }
+
+// Cross product with another vector
+func (v *Vector3D) Cross(u *Vector3D) *Vector3D {
+	return &Vector3D{
+		(v.Y * u.Z) - (v.Z * u.Y),
+		(v.Z * u.X) - (v.X * u.Z),
+		(v.X * u.Y) - (v.Y * u.X),
+	}
+}
+
+// Add returns the sum of the vector and another vector
+func (v *Vector3D) Add(u *Vector3D) *Vector3D {
+	return &Vector3D{v.X + u.X, v.Y + u.Y, v.Z + u.Z}
+}
+
+// Sub returns the difference of the vector and another vector
+func (v *Vector3D) Sub(u *Vector3D) *Vector3D {
+	return &Vector3D{v.X - u.X, v.Y - u.Y, v.Z - u.Z}
+}
+
+// Scale returns the vector multiplied by a scalar
+func (v *Vector3D) Scale(s float64) *Vector3D {
+	return &Vector3D{v.X * s, v.Y * s, v.Z * s}
+}
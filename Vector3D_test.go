@@ -39,3 +39,24 @@ func TestUnitVector(t *testing.T) {
 	assert.False(t, ok, "unit vector fail")
 	assert.Nil(t, vec, "unit vector fail")
 }
+
+func TestCrossProduct(t *testing.T) {
+	v1 := NewVector(1, 0, 0)
+	v2 := NewVector(0, 1, 0)
+	assert.Equal(t, NewVector(0, 0, 1), v1.Cross(v2), "x cross y is z")
+	assert.Equal(t, NewVector(0, 0, -1), v2.Cross(v1), "y cross x is -z")
+
+	v3 := NewVector(1, 2, 3)
+	v4 := NewVector(4, 5, 6)
+	assert.Equal(t, NewVector(-3, 6, -3), v3.Cross(v4), "cross product")
+	assert.Equal(t, 0.0, v3.Cross(v4).Dot(v3), "cross product is perpendicular")
+}
+
+func TestVectorArithmetic(t *testing.T) {
+	v1 := NewVector(1, 2, 3)
+	v2 := NewVector(4, 5, 6)
+	assert.Equal(t, NewVector(5, 7, 9), v1.Add(v2), "vector sum")
+	assert.Equal(t, NewVector(3, 3, 3), v2.Sub(v1), "vector difference")
+	assert.Equal(t, NewVector(2, 4, 6), v1.Scale(2), "scaled vector")
+	assert.Equal(t, NewVector(1, 2, 3), v1, "operations don't modify the vector")
+}
@@ -1,5 +1,12 @@
 package dlineate
 
+import (
+	"errors"
+
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// Workplane places a sketch in 3D space. The sketch's X and Y axes run along xDir and yDir from origin.
 type Workplane struct {
 	origin *Vector3D
 	xDir   *Vector3D
@@ -13,3 +20,108 @@ func NewWorkPlane(origin *Vector3D, xDir *Vector3D, yDir *Vector3D) *Workplane {
 	wp.yDir = yDir
 	return wp
 }
+
+// defaultWorkplane returns the XY plane at the 3D origin
+func defaultWorkplane() *Workplane {
+	return NewWorkPlane(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+}
+
+// Origin returns the 3D location of the sketch origin
+func (wp *Workplane) Origin() *Vector3D {
+	return wp.origin
+}
+
+// XDir returns the 3D direction of the sketch's X axis
+func (wp *Workplane) XDir() *Vector3D {
+	return wp.xDir
+}
+
+// YDir returns the 3D direction of the sketch's Y axis
+func (wp *Workplane) YDir() *Vector3D {
+	return wp.yDir
+}
+
+// Normal returns the unit normal of the workplane, xDir cross yDir
+func (wp *Workplane) Normal() *Vector3D {
+	n, _ := wp.xDir.Cross(wp.yDir).UnitVector()
+	return n
+}
+
+// Validate returns an error if the workplane can't place a sketch: a direction is missing or has no length or the
+// directions are parallel
+func (wp *Workplane) Validate() error {
+	_, err := wp.orthonormalize()
+	return err
+}
+
+// orthonormalize returns a copy of the workplane with unit length, perpendicular axis directions.
+// xDir keeps its direction and yDir is adjusted to be perpendicular to it within the same plane.
+// It returns an error if a direction is missing or has no length or if the directions are parallel.
+func (wp *Workplane) orthonormalize() (*Workplane, error) {
+	if wp == nil {
+		return nil, errors.New("workplane must not be nil")
+	}
+	if wp.origin == nil || wp.xDir == nil || wp.yDir == nil {
+		return nil, errors.New("workplane requires an origin, x direction and y direction")
+	}
+	xDir, ok := wp.xDir.UnitVector()
+	if !ok {
+		return nil, errors.New("workplane x direction has no length")
+	}
+	yDir, ok := wp.yDir.UnitVector()
+	if !ok {
+		return nil, errors.New("workplane y direction has no length")
+	}
+	if utils.StandardFloatCompare(xDir.Cross(yDir).Magnitude(), 0) == 0 {
+		return nil, errors.New("workplane x and y directions are parallel")
+	}
+	// Remove the part of yDir along xDir (Gram-Schmidt)
+	yDir, _ = yDir.Sub(xDir.Scale(yDir.Dot(xDir))).UnitVector()
+	origin := *wp.origin
+	return NewWorkPlane(&origin, xDir, yDir), nil
+}
+
+// to3D maps sketch coordinates onto the workplane
+func (wp *Workplane) to3D(x float64, y float64) *Vector3D {
+	return wp.origin.Add(wp.xDir.Scale(x)).Add(wp.yDir.Scale(y))
+}
+
+// projectTo2D returns the sketch coordinates of a 3D point projected onto the workplane
+func (wp *Workplane) projectTo2D(v *Vector3D) (float64, float64) {
+	offset := v.Sub(wp.origin)
+	return offset.Dot(wp.xDir), offset.Dot(wp.yDir)
+}
+
+// To3D maps the sketch coordinates [x, y] to a 3D point on the sketch's workplane.
+// Coordinates and the workplane origin are in the sketch's length unit.
+func (s *Sketch) To3D(x float64, y float64) *Vector3D {
+	return s.plane.to3D(x, y)
+}
+
+// ProjectTo2D returns the sketch coordinates of the 3D point v projected onto the sketch's workplane
+func (s *Sketch) ProjectTo2D(v *Vector3D) (float64, float64) {
+	return s.plane.projectTo2D(v)
+}
+
+// Values3D returns the points of an element mapped onto the workplane of the sketch s.
+// Points are [p], lines are [start, end], circles are [center] and arcs are [center, start, end].
+// Axes are [origin, origin + direction] where origin is the point on the axis nearest the sketch origin.
+func (e *Element) Values3D(s *Sketch) []*Vector3D {
+	values := e.Values()
+	if e.elementType == Axis {
+		a, b, c := values[0], values[1], values[2]
+		scale := -c / ((a * a) + (b * b))
+		x, y := scale*a, scale*b
+		dx, dy, _ := e.direction()
+		values = []float64{x, y, x + dx, y + dy}
+	}
+	if e.elementType == Circle {
+		values = values[:2]
+	}
+
+	points := make([]*Vector3D, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		points = append(points, s.To3D(values[i], values[i+1]))
+	}
+	return points
+}
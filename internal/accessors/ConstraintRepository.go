@@ -9,6 +9,7 @@ import (
 type ConstraintRepository struct {
 	constraints map[uint]*constraint.Constraint
 	eToC        map[uint][]*constraint.Constraint
+	nextId      uint
 }
 
 func NewConstraintRepository() *ConstraintRepository {
@@ -20,6 +21,7 @@ func NewConstraintRepository() *ConstraintRepository {
 func (r *ConstraintRepository) Clear() {
 	r.constraints = make(map[uint]*constraint.Constraint, 0)
 	r.eToC = make(map[uint][]*constraint.Constraint, 0)
+	r.nextId = 0
 }
 
 func (r *ConstraintRepository) GetConstraint(cId uint) (*constraint.Constraint, bool) {
@@ -29,6 +31,9 @@ func (r *ConstraintRepository) GetConstraint(cId uint) (*constraint.Constraint,
 
 func (r *ConstraintRepository) AddConstraint(c *constraint.Constraint) *constraint.Constraint {
 	r.constraints[c.GetID()] = c
+	if c.GetID() >= r.nextId {
+		r.nextId = c.GetID() + 1
+	}
 	if _, ok := r.eToC[c.Element1]; !ok {
 		r.eToC[c.Element1] = make([]*constraint.Constraint, 0)
 	}
@@ -79,8 +84,9 @@ func (r *ConstraintRepository) SetConstraintElement(oldId uint, newElement uint)
 	r.eToC[newElement] = append(r.eToC[newElement], r.eToC[oldId]...)
 }
 
+// NextId returns an id which has not been used by a constraint, including removed constraints
 func (r *ConstraintRepository) NextId() uint {
-	return uint(len(r.constraints))
+	return r.nextId
 }
 
 func (r *ConstraintRepository) IdSet() *utils.Set {
@@ -31,8 +31,13 @@ func (r *ElementRepository) Clear() {
 	r.elementClusters = make(map[uint]*utils.Set)
 }
 
+// ClearClusters removes the cluster copies of elements and each element's cluster membership so clusters can
+// be built again without elements appearing shared with clusters that no longer exist
 func (r *ElementRepository) ClearClusters() {
 	r.clusterElements = make(map[int]map[uint]el.SketchElement, 0)
+	for eId := range r.elementClusters {
+		r.elementClusters[eId] = utils.NewSet()
+	}
 }
 
 func (r *ElementRepository) GetElement(cId int, eId uint) (el.SketchElement, bool) {
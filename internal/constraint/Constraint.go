@@ -30,6 +30,29 @@ func (t Type) String() string {
 	}
 }
 
+// Bound of a Constraint (Exact, Minimum or Maximum)
+type Bound uint
+
+// Bound constants
+const (
+	Exact Bound = iota
+	Minimum
+	Maximum
+)
+
+func (b Bound) String() string {
+	switch b {
+	case Exact:
+		return "Exact"
+	case Minimum:
+		return "Minimum"
+	case Maximum:
+		return "Maximum"
+	default:
+		return fmt.Sprintf("%d", int(b))
+	}
+}
+
 // Constraint interface representing a constraint
 /*type Constraint interface {
 	SetID(uint)
@@ -58,6 +81,7 @@ type Constraint struct {
 	Element1 uint
 	Element2 uint
 	Solved   bool
+	Bound    Bound
 }
 
 // GetID returns the constraint identifier
@@ -131,25 +155,73 @@ func (c *Constraint) Shared(o *Constraint) (uint, bool) {
 	return 0, false
 }
 
-func (c *Constraint) IsMet(e1 el.SketchElement, e2 el.SketchElement) bool {
-	var temp big.Float
-	current := e1.DistanceTo(e2)
+// measure returns the unsigned distance or angle between two elements used to check inequality constraints
+func (c *Constraint) measure(e1 el.SketchElement, e2 el.SketchElement) float64 {
 	if c.Type == Angle {
-		current = e1.AsLine().AngleToLine(e2.AsLine())
+		current, _ := e1.AsLine().AngleToLine(e2.AsLine()).Float64()
+		return math.Abs(current)
+	}
+	first := e1
+	other := e2
+	// If using the numerical solver, elements could be segments so convert
+	if e1.GetType() == el.Line {
+		first = e1.AsLine()
+	}
+	if e2.GetType() == el.Line {
+		other = e2.AsLine()
+	}
+	current, _ := first.DistanceTo(other).Float64()
+	return math.Abs(current)
+}
+
+// violation returns how far an inequality constraint is from being met or 0 if it is met
+func (c *Constraint) violation(e1 el.SketchElement, e2 el.SketchElement) float64 {
+	current := c.measure(e1, e2)
+	value, _ := c.Value.Float64()
+	switch c.Bound {
+	case Minimum:
+		return math.Max(value-current, 0)
+	case Maximum:
+		return math.Max(current-value, 0)
 	}
+	return math.Abs(current - value)
+}
+
+// IsAtBound returns whether an inequality constraint's elements are at its limit within the
+// tolerance used by the numeric solver
+func (c *Constraint) IsAtBound(e1 el.SketchElement, e2 el.SketchElement) bool {
+	value, _ := c.Value.Float64()
+	return math.Abs(c.measure(e1, e2)-value) <= math.Sqrt(utils.StandardCompare)
+}
 
-	comparison := utils.StandardBigFloatCompare(temp.Abs(current), temp.Abs(&c.Value))
-	if comparison != 0 {
-		c.Solved = false
-	} else {
-		c.Solved = true
+func (c *Constraint) IsMet(e1 el.SketchElement, e2 el.SketchElement) bool {
+	if c.Bound != Exact {
+		c.Solved = utils.StandardFloatCompare(c.violation(e1, e2), 0) == 0
+		return c.Solved
+	}
+	if c.Type == Angle {
+		// Angles are signed -- the direction of each line matters
+		current, _ := e1.AsLine().AngleToLine(e2.AsLine()).Float64()
+		desired, _ := c.Value.Float64()
+		c.Solved = utils.StandardAngleCompare(current, desired) == 0
+		return c.Solved
 	}
 
+	var current, desired big.Float
+	current.Abs(e1.DistanceTo(e2))
+	desired.Abs(&c.Value)
+	c.Solved = utils.StandardBigFloatCompare(&current, &desired) == 0
+
 	return c.Solved
 }
 
 func (c *Constraint) Error(e1 el.SketchElement, e2 el.SketchElement) float64 {
 	var result float64
+	// Inequalities are a penalty which is 0 anywhere the constraint is met
+	if c.Bound != Exact {
+		result = c.violation(e1, e2)
+		return result * result
+	}
 	switch c.Type {
 	case Angle:
 		// Returns a value between -Pi and Pi
@@ -157,22 +229,11 @@ func (c *Constraint) Error(e1 el.SketchElement, e2 el.SketchElement) float64 {
 
 		Lv, _ := current.Float64()
 		Sv, _ := c.Value.Float64()
-		if Sv > Lv {
-			Lv, Sv = Sv, Lv
-		}
-
-		// Compare crossing -Pi / Pi boundary counting -Pi as equal to Pi
-		// Always positive in the range [0, 2pi)
-		dist1 := (Sv + math.Pi) + (math.Pi - Lv)
 
-		// Direct compare -- always positive in the range [0, 2pi)
-		dist2 := Lv - Sv
-
-		// Take the smaller of the two values. This results in an error in the range [0, pi)
-		result = dist1
-		if dist2 < result {
-			result = dist2
-		}
+		// Angles are signed, so compare the direction of rotation as well as its magnitude.
+		// Crossing the -Pi / Pi boundary is handled by counting -Pi as equal to Pi
+		// This results in an error in the range [0, pi]
+		result = math.Abs(utils.AngleDifference(Lv, Sv))
 		// utils.Logger.Debug().
 		// 	Uint("constraint id", c.GetID()).
 		// 	Float64("angle difference", result).
@@ -225,7 +286,11 @@ func (c *Constraint) String() string {
 	if c.Type == Angle {
 		units = " rad"
 	}
-	return fmt.Sprintf("Constraint(%d) type: %v, e1: %d, e2: %d, v: %s%s", c.GetID(), c.Type, c.Element1, c.Element2, c.Value.String(), units)
+	bound := ""
+	if c.Bound != Exact {
+		bound = fmt.Sprintf(" (%v)", c.Bound)
+	}
+	return fmt.Sprintf("Constraint(%d) type: %v%s, e1: %d, e2: %d, v: %s%s", c.GetID(), c.Type, bound, c.Element1, c.Element2, c.Value.String(), units)
 }
 
 func (c *Constraint) ToGraphViz(cId1, cId2 int) string {
@@ -257,7 +322,7 @@ func NewConstraint(id uint, constraintType Type, a uint, b uint, v *big.Float, s
 // CopyConstraint creates a deep copy of a Constraint
 func CopyConstraint(c *Constraint) *Constraint {
 	var temp big.Float
-	return NewConstraint(
+	n := NewConstraint(
 		c.GetID(),
 		c.Type,
 		c.Element1,
@@ -265,6 +330,8 @@ func CopyConstraint(c *Constraint) *Constraint {
 		temp.Copy(&c.Value),
 		c.Solved,
 	)
+	n.Bound = c.Bound
+	return n
 }
 
 type ConstraintList []*Constraint
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	el "github.com/marcuswu/dlineate/internal/element"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 )
@@ -189,3 +190,65 @@ func TestCopyAndSort(t *testing.T) {
 
 	log.Logger.Trace().Array("test", constraintList)
 }
+
+func TestSignedAngleIsMet(t *testing.T) {
+	// Line along +x and line along +y
+	l1 := el.NewSketchLine(0, big.NewFloat(0), big.NewFloat(-1), big.NewFloat(0))
+	l2 := el.NewSketchLine(1, big.NewFloat(1), big.NewFloat(0), big.NewFloat(0))
+
+	c := NewConstraint(0, Angle, 0, 1, big.NewFloat(math.Pi/2), false)
+	assert.True(t, c.IsMet(l1, l2), "Counter-clockwise angle from l1 to l2 is 90 degrees")
+	assert.False(t, c.IsMet(l2, l1), "Counter-clockwise angle from l2 to l1 is -90 degrees")
+	assert.InDelta(t, 0.0, c.Error(l1, l2), 0.00001, "No error for a met angle constraint")
+	assert.InDelta(t, math.Pi*math.Pi, c.Error(l2, l1), 0.00001, "Reversed angle is pi radians off")
+
+	// Line along -x is pi radians from l1 regardless of the sign used
+	l3 := el.NewSketchLine(2, big.NewFloat(0), big.NewFloat(1), big.NewFloat(0))
+	c = NewConstraint(1, Angle, 0, 2, big.NewFloat(-math.Pi), false)
+	assert.True(t, c.IsMet(l1, l3), "-pi and pi are the same angle")
+	c = NewConstraint(2, Angle, 0, 2, big.NewFloat(0), false)
+	assert.False(t, c.IsMet(l1, l3), "Lines in opposite directions are not at 0 degrees")
+
+	c = NewConstraint(3, Distance, 0, 1, big.NewFloat(2), false)
+	p1 := el.NewSketchPoint(3, big.NewFloat(0), big.NewFloat(0))
+	p2 := el.NewSketchPoint(4, big.NewFloat(0), big.NewFloat(1))
+	assert.False(t, c.IsMet(p1, p2), "Distance constraint is not met")
+	p2 = el.NewSketchPoint(4, big.NewFloat(0), big.NewFloat(-2))
+	assert.True(t, c.IsMet(p1, p2), "Distance constraint is met")
+}
+
+func TestBoundIsMet(t *testing.T) {
+	p1 := el.NewSketchPoint(0, big.NewFloat(0), big.NewFloat(0))
+	p2 := el.NewSketchPoint(1, big.NewFloat(0), big.NewFloat(3))
+
+	c := NewConstraint(0, Distance, 0, 1, big.NewFloat(2), false)
+	c.Bound = Minimum
+	assert.True(t, c.IsMet(p1, p2), "Distance is more than the minimum")
+	assert.InDelta(t, 0.0, c.Error(p1, p2), 0.00001, "No error for a met minimum")
+	assert.False(t, c.IsAtBound(p1, p2), "Distance is not at the minimum")
+	c.Bound = Maximum
+	assert.False(t, c.IsMet(p1, p2), "Distance is more than the maximum")
+	assert.InDelta(t, 1.0, c.Error(p1, p2), 0.00001, "Error is the square of the violation")
+
+	c = NewConstraint(1, Distance, 0, 1, big.NewFloat(3), false)
+	c.Bound = Maximum
+	assert.True(t, c.IsMet(p1, p2), "Distance at the maximum is met")
+	assert.True(t, c.IsAtBound(p1, p2), "Distance is at the maximum")
+	assert.Equal(t, "Constraint(1) type: Distance (Maximum), e1: 0, e2: 1, v: 3", c.String())
+	assert.Equal(t, Maximum, CopyConstraint(c).Bound, "Copies keep the bound")
+
+	// Angle bounds ignore the direction of rotation
+	l1 := el.NewSketchLine(2, big.NewFloat(0), big.NewFloat(-1), big.NewFloat(0))
+	l2 := el.NewSketchLine(3, big.NewFloat(1), big.NewFloat(0), big.NewFloat(0))
+	c = NewConstraint(2, Angle, 2, 3, big.NewFloat(math.Pi/4), false)
+	c.Bound = Minimum
+	assert.True(t, c.IsMet(l1, l2), "90 degrees is more than the minimum")
+	assert.True(t, c.IsMet(l2, l1), "-90 degrees is more than the minimum")
+	c.Bound = Maximum
+	assert.False(t, c.IsMet(l2, l1), "-90 degrees is more than the maximum")
+
+	assert.Equal(t, "Exact", Exact.String())
+	assert.Equal(t, "Minimum", Minimum.String())
+	assert.Equal(t, "Maximum", Maximum.String())
+	assert.Equal(t, "5", Bound(5).String())
+}
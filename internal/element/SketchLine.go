@@ -9,6 +9,10 @@ import (
 
 // SketchLine represents a line in a 2D sketch in the form
 // Ax + By + C = 0. A and B are represented as x and y in the BaseElement
+// (A, B) is the normal of the line. The direction of the line is the normal rotated
+// 90 degrees counter-clockwise, (-B, A), which runs from Start towards End for lines
+// created from two points (see utils.LineFromPoints). Negating A, B, and C describes
+// the same set of points, but reverses the line's direction.
 type SketchLine struct {
 	elementType     Type
 	id              uint
@@ -316,17 +320,19 @@ func (l *SketchLine) AngleTo(u *Vector) *big.Float {
 	return lv.AngleTo(u)
 }
 
+// Direction returns a vector along the line in its direction of travel, (-B, A)
+func (l *SketchLine) Direction() *Vector {
+	var x, y big.Float
+	x.Neg(l.GetB())
+	y.Set(l.GetA())
+	return &Vector{x, y}
+}
+
 // AngleToLine returns the angle the line needs to rotate to be equivalent to to another line in radians
-// The resulting angle will be -pi to pi
+// The resulting angle will be -pi to pi. Line directions are taken into account, so lines
+// pointing in opposite directions are pi radians apart.
 func (l *SketchLine) AngleToLine(o *SketchLine) *big.Float {
-	var lx, ly, ox, oy big.Float
-	lx.Set(l.GetB())
-	ly.Neg(l.GetA())
-	lv := &Vector{lx, ly}
-	ox.Set(o.GetB())
-	oy.Neg(o.GetA())
-	ov := &Vector{ox, oy}
-	return lv.AngleTo(ov)
+	return l.Direction().AngleTo(o.Direction())
 }
 
 // Rotated returns a line representing this line rotated around the origin by angle radians
@@ -69,6 +69,21 @@ func TestAngleToLine(t *testing.T) {
 	}
 }
 
+func TestDirection(t *testing.T) {
+	a, b, c := utils.BigFloatLineFromPoints(2, 1, -1, 1)
+	l1 := NewSketchLine(0, a, b, c)
+	dir := l1.Direction()
+	x, _ := dir.X.Float64()
+	y, _ := dir.Y.Float64()
+	assert.InDelta(t, -1.0, x, utils.StandardCompare, "Line direction runs from start to end")
+	assert.InDelta(t, 0.0, y, utils.StandardCompare, "Line direction runs from start to end")
+
+	a, b, c = utils.BigFloatLineFromPoints(-1, 1, 2, 1)
+	l2 := NewSketchLine(1, a, b, c)
+	angle, _ := l1.AngleToLine(l2).Float64()
+	assert.InDelta(t, math.Pi, math.Abs(angle), utils.StandardCompare, "Reversed lines are pi radians apart")
+}
+
 func TestNearestPoint(t *testing.T) {
 	tests := []struct {
 		line    *SketchLine
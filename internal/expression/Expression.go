@@ -0,0 +1,416 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lookup returns the value of a named variable in an expression
+type Lookup func(name string) (float64, error)
+
+// Expression is a parsed arithmetic expression of numbers and named variables.
+// It supports +, -, *, /, ^ (power), parentheses and the functions sqrt, abs, sin, cos and tan.
+// Trigonometric functions take degrees unless evaluated with EvaluateAngles.
+type Expression struct {
+	source string
+	root   node
+}
+
+type node interface {
+	evaluate(env environment) (float64, error)
+	variables(names map[string]bool)
+}
+
+// environment holds the variables and the angle unit used to evaluate an expression
+type environment struct {
+	lookup Lookup
+	// radians in one unit of the angles passed to trigonometric functions
+	angle float64
+}
+
+var functions = map[string]func(float64) (float64, error){
+	"sqrt": func(v float64) (float64, error) {
+		if v < 0 {
+			return 0, errors.New("square root of a negative number")
+		}
+		return math.Sqrt(v), nil
+	},
+	"abs": func(v float64) (float64, error) { return math.Abs(v), nil },
+	"sin": func(v float64) (float64, error) { return math.Sin(v), nil },
+	"cos": func(v float64) (float64, error) { return math.Cos(v), nil },
+	"tan": func(v float64) (float64, error) { return math.Tan(v), nil },
+}
+
+// angleFunctions are the functions taking an angle, which is converted to radians before they are called
+var angleFunctions = map[string]bool{"sin": true, "cos": true, "tan": true}
+
+// Parse parses an expression such as "2 * height + 3"
+func Parse(source string) (*Expression, error) {
+	p := &parser{source: source}
+	p.next()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind != tokenEnd {
+		return nil, p.errorf("unexpected %q", p.token.text)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Number returns an expression for a constant value
+func Number(v float64) *Expression {
+	return &Expression{source: strconv.FormatFloat(v, 'g', -1, 64), root: number(v)}
+}
+
+// IsName returns whether a string can be used as a variable name in an expression
+func IsName(name string) bool {
+	if name == "" || functions[name] != nil {
+		return false
+	}
+	for i, r := range name {
+		if !isNameRune(r, i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate returns the value of the expression using lookup for the value of variables.
+// Trigonometric functions take degrees.
+func (e *Expression) Evaluate(lookup Lookup) (float64, error) {
+	return e.EvaluateAngles(lookup, math.Pi/180)
+}
+
+// EvaluateAngles returns the value of the expression like Evaluate with trigonometric functions taking
+// angles in a unit of the given number of radians, such as 1 for radians or math.Pi / 180 for degrees.
+func (e *Expression) EvaluateAngles(lookup Lookup, radians float64) (float64, error) {
+	return e.root.evaluate(environment{lookup: lookup, angle: radians})
+}
+
+// Variables returns the sorted names of the variables used in the expression
+func (e *Expression) Variables() []string {
+	names := make(map[string]bool)
+	e.root.variables(names)
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+type number float64
+
+func (n number) evaluate(env environment) (float64, error) {
+	return float64(n), nil
+}
+
+func (n number) variables(names map[string]bool) {}
+
+type variable string
+
+func (v variable) evaluate(env environment) (float64, error) {
+	if env.lookup == nil {
+		return 0, fmt.Errorf("unknown parameter %q", string(v))
+	}
+	return env.lookup(string(v))
+}
+
+func (v variable) variables(names map[string]bool) {
+	names[string(v)] = true
+}
+
+type unary struct {
+	op      rune
+	operand node
+}
+
+func (u unary) evaluate(env environment) (float64, error) {
+	v, err := u.operand.evaluate(env)
+	if u.op == '-' {
+		v = -v
+	}
+	return v, err
+}
+
+func (u unary) variables(names map[string]bool) {
+	u.operand.variables(names)
+}
+
+type binary struct {
+	op          rune
+	left, right node
+}
+
+func (b binary) evaluate(env environment) (float64, error) {
+	left, err := b.left.evaluate(env)
+	if err != nil {
+		return 0, err
+	}
+	right, err := b.right.evaluate(env)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		if right == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return left / right, nil
+	case '^':
+		return math.Pow(left, right), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", b.op)
+}
+
+func (b binary) variables(names map[string]bool) {
+	b.left.variables(names)
+	b.right.variables(names)
+}
+
+type call struct {
+	name     string
+	argument node
+}
+
+func (c call) evaluate(env environment) (float64, error) {
+	v, err := c.argument.evaluate(env)
+	if err != nil {
+		return 0, err
+	}
+	if angleFunctions[c.name] {
+		v *= env.angle
+	}
+	return functions[c.name](v)
+}
+
+func (c call) variables(names map[string]bool) {
+	c.argument.variables(names)
+}
+
+type tokenKind uint
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenName
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+}
+
+// parser is a recursive descent parser for expressions
+type parser struct {
+	source   string
+	position int
+	token    token
+	err      error
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("expression %q: %s", p.source, fmt.Sprintf(format, args...))
+}
+
+func isNameRune(r rune, first bool) bool {
+	return r == '_' || unicode.IsLetter(r) || (!first && unicode.IsDigit(r))
+}
+
+// runeAt decodes the UTF-8 rune at a position in the source along with its width in bytes
+func (p *parser) runeAt(position int) (rune, int) {
+	return utf8.DecodeRuneInString(p.source[position:])
+}
+
+// isDigit returns whether a byte is an ASCII digit, the only digits numbers are written with
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// next reads the next token from the source
+func (p *parser) next() {
+	for p.position < len(p.source) {
+		r, width := p.runeAt(p.position)
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.position += width
+	}
+	if p.position >= len(p.source) {
+		p.token = token{kind: tokenEnd, text: "end of expression"}
+		return
+	}
+
+	start := p.position
+	r, width := p.runeAt(start)
+	switch {
+	case isDigit(p.source[start]) || r == '.':
+		end := start
+		for end < len(p.source) && (isDigit(p.source[end]) || p.source[end] == '.') {
+			end++
+		}
+		// Exponent
+		if end < len(p.source) && (p.source[end] == 'e' || p.source[end] == 'E') {
+			exp := end + 1
+			if exp < len(p.source) && (p.source[exp] == '+' || p.source[exp] == '-') {
+				exp++
+			}
+			if exp < len(p.source) && isDigit(p.source[exp]) {
+				end = exp
+				for end < len(p.source) && isDigit(p.source[end]) {
+					end++
+				}
+			}
+		}
+		text := p.source[start:end]
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil && p.err == nil {
+			p.err = p.errorf("invalid number %q", text)
+		}
+		p.position = end
+		p.token = token{kind: tokenNumber, text: text, value: value}
+	case isNameRune(r, true):
+		end := start + width
+		for end < len(p.source) {
+			next, nextWidth := p.runeAt(end)
+			if !isNameRune(next, false) {
+				break
+			}
+			end += nextWidth
+		}
+		p.position = end
+		p.token = token{kind: tokenName, text: p.source[start:end]}
+	default:
+		p.position += width
+		p.token = token{kind: tokenOperator, text: string(r)}
+	}
+}
+
+func (p *parser) isOperator(ops string) bool {
+	return p.token.kind == tokenOperator && strings.Contains(ops, p.token.text)
+}
+
+// parseSum parses terms separated by + and -
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+-") {
+		op := rune(p.token.text[0])
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseProduct parses factors separated by * and /
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*/") {
+		op := rune(p.token.text[0])
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses a leading + or - and powers
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("+-") {
+		op := rune(p.token.text[0])
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: op, operand: operand}, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower parses a ^ b where ^ is right associative
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		p.next()
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binary{op: '^', left: base, right: exponent}, nil
+	}
+	return base, nil
+}
+
+// parsePrimary parses numbers, variables, function calls and parenthesized expressions
+func (p *parser) parsePrimary() (node, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	switch {
+	case p.token.kind == tokenNumber:
+		n := number(p.token.value)
+		p.next()
+		return n, nil
+	case p.token.kind == tokenName:
+		name := p.token.text
+		p.next()
+		if functions[name] == nil {
+			return variable(name), nil
+		}
+		if !p.isOperator("(") {
+			return nil, p.errorf("expected ( after %s", name)
+		}
+		argument, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return call{name: name, argument: argument}, nil
+	case p.isOperator("("):
+		p.next()
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOperator(")") {
+			return nil, p.errorf("expected ) but found %q", p.token.text)
+		}
+		p.next()
+		return inner, nil
+	}
+	return nil, p.errorf("unexpected %q", p.token.text)
+}
@@ -0,0 +1,101 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	values := map[string]float64{"height": 10, "width_2": 4, "höhe": 3, "Δx": 2}
+	lookup := func(name string) (float64, error) {
+		v, ok := values[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown parameter %q", name)
+		}
+		return v, nil
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		expected float64
+	}{
+		{"number", "5", 5},
+		{"decimal", " 2.5 ", 2.5},
+		{"exponent", "1e2", 100},
+		{"variable", "height", 10},
+		{"precedence", "2 * height + 3", 23},
+		{"parentheses", "2 * (height + 3)", 26},
+		{"left associative", "10 - 4 - 3", 3},
+		{"division", "height / width_2", 2.5},
+		{"unary", "-height + +2", -8},
+		{"power", "2 ^ 3 ^ 2", 512},
+		{"negative power", "-2 ^ 2", -4},
+		{"function", "sqrt(width_2) + abs(-1)", 3},
+		{"degrees", "cos(60) * 2", 1},
+		{"unicode variables", "höhe*Δx + 1", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.source)
+			assert.Nil(t, err)
+			v, err := e.Evaluate(lookup)
+			assert.Nil(t, err)
+			assert.InDelta(t, tt.expected, v, 1e-12)
+			assert.Equal(t, tt.source, e.String())
+		})
+	}
+
+	e, _ := Parse("cos(60) * 2")
+	v, err := e.EvaluateAngles(lookup, 1)
+	assert.Nil(t, err)
+	assert.InDelta(t, math.Cos(60)*2, v, 1e-12, "Trigonometric functions take the given angle unit")
+	e, _ = Parse("sin(height / width_2) + tan(0)")
+	v, _ = e.EvaluateAngles(lookup, 1)
+	assert.InDelta(t, math.Sin(2.5), v, 1e-12, "Angle units apply to expression arguments")
+
+	e, _ = Parse("height / (width_2 - 4)")
+	_, err = e.Evaluate(lookup)
+	assert.NotNil(t, err, "Division by zero is an error")
+	e, _ = Parse("depth * 2")
+	_, err = e.Evaluate(lookup)
+	assert.NotNil(t, err, "Unknown variables are an error")
+	_, err = e.Evaluate(nil)
+	assert.NotNil(t, err, "Variables without a lookup are an error")
+	e, _ = Parse("sqrt(0 - height)")
+	_, err = e.Evaluate(lookup)
+	assert.NotNil(t, err, "Square root of a negative number is an error")
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{"", "2 +", "(2 + 3", "2 3", "1.2.3", "sqrt 4", "2 $ 3", ")", "2 × 3", "2é"} {
+		_, err := Parse(source)
+		assert.NotNil(t, err, "Expected an error parsing %q", source)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	e, err := Parse("b * (a + b) - sqrt(c) + 2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, e.Variables())
+	assert.Equal(t, []string{}, Number(3).Variables())
+
+	v, err := Number(3.5).Evaluate(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3.5, v)
+	assert.Equal(t, "3.5", Number(3.5).String())
+}
+
+func TestIsName(t *testing.T) {
+	assert.True(t, IsName("height"))
+	assert.True(t, IsName("_width2"))
+	assert.False(t, IsName(""))
+	assert.False(t, IsName("2width"))
+	assert.False(t, IsName("wid th"))
+	assert.False(t, IsName("sqrt"), "Function names are reserved")
+	assert.True(t, IsName("höhe"), "Names may use letters outside ASCII")
+	assert.False(t, IsName("x²"), "Names don't contain symbols")
+}
@@ -253,6 +253,11 @@ func (g *GraphCluster) Solve(ea accessors.ElementAccessor, ca accessors.Constrai
 		if c2.HasElementID(c1.Element2) {
 			eId = c1.Element2
 		}
+		// Two constraints placing one point on another solve for whichever point isn't placed yet
+		if c2.HasElements(c1.Element1, c1.Element2) && solver.IsPointOnPoint(c1) && solver.IsPointOnPoint(c2) &&
+			g.isElementPlaced(ca, eId) {
+			eId, _ = c1.Other(eId)
+		}
 		if !c2.HasElementID(eId) {
 			utils.Logger.Error().
 				Uint("constraint 1", c[0].GetID()).
@@ -316,6 +321,16 @@ func (g *GraphCluster) Solve(ea accessors.ElementAccessor, ca accessors.Constrai
 	return state
 }
 
+// isElementPlaced returns whether the constraints solved so far in the cluster have placed an element
+func (g *GraphCluster) isElementPlaced(ca accessors.ConstraintAccessor, eId uint) bool {
+	for _, cId := range g.solved.Contents() {
+		if c, ok := ca.GetConstraint(cId); ok && c.HasElementID(eId) {
+			return true
+		}
+	}
+	return false
+}
+
 // SolveMerge resolves merging two solved child clusters to this one
 /* TODO: Rewrite this. I originally wrote this when I couldn't solve for a line and had to
 solve lines separately and then solve for a point. Now I can solve for a line.
@@ -54,7 +54,6 @@ func (g *GraphCluster) mergeConstraint(ea accessors.ElementAccessor, anchor uint
 	solveElement, _ := ea.GetElement(g.GetID(), solveFor)
 	if anchorElement.GetType() == el.Line && solveElement.GetType() == el.Line {
 		angle := anchorElement.AsLine().AngleToLine(solveElement.AsLine())
-		angle.Neg(angle)
 		utils.Logger.Trace().
 			Uint("element 1", anchor).
 			Uint("element 2", solveFor).
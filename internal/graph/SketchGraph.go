@@ -7,6 +7,7 @@ import (
 	"github.com/marcuswu/dlineate/internal/accessors"
 	"github.com/marcuswu/dlineate/internal/constraint"
 	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
 	"github.com/marcuswu/dlineate/internal/solver"
 	"github.com/marcuswu/dlineate/utils"
 	"github.com/rs/zerolog"
@@ -23,6 +24,8 @@ type SketchGraph struct {
 	state            solver.SolveState
 	degreesOfFreedom uint
 	conflicting      *utils.Set
+	inequalities     []*constraint.Constraint  // Enforced by the numeric solver after the graph solve
+	loops            []*numeric.LoopConstraint // Area and perimeter constraints enforced with the inequalities
 }
 
 func NewSketch() *SketchGraph {
@@ -35,6 +38,8 @@ func NewSketch() *SketchGraph {
 	g.state = solver.None
 	g.degreesOfFreedom = 6
 	g.conflicting = utils.NewSet()
+	g.inequalities = make([]*constraint.Constraint, 0)
+	g.loops = make([]*numeric.LoopConstraint, 0)
 	return g
 }
 
@@ -48,6 +53,11 @@ func (g *SketchGraph) GetConstraint(id uint) (*constraint.Constraint, bool) {
 	return g.constraintAccessor.GetConstraint(id)
 }
 
+// ConstraintsForElement gets the constraints in the graph referencing an element
+func (g *SketchGraph) ConstraintsForElement(id uint) []*constraint.Constraint {
+	return g.constraintAccessor.ConstraintsForElement(id)
+}
+
 func (g *SketchGraph) MakeFixed(e el.SketchElement) {
 	e.SetFixed(true)
 }
@@ -164,6 +174,17 @@ func (g *SketchGraph) CombinePoints(e1 el.SketchElement, e2 el.SketchElement) el
 	}
 
 	g.constraintAccessor.SetConstraintElement(rem.GetID(), keep.GetID())
+	for _, c := range g.inequalities {
+		if c.Element1 == rem.GetID() {
+			c.Element1 = keep.GetID()
+		}
+		if c.Element2 == rem.GetID() {
+			c.Element2 = keep.GetID()
+		}
+	}
+	for _, l := range g.loops {
+		l.ReplacePoint(rem.GetID(), keep.GetID())
+	}
 
 	// remove e2 from freenodes, elements
 	// g.freeNodes.Remove(rem.GetID())
@@ -191,6 +212,18 @@ func (g *SketchGraph) AddConstraint(t constraint.Type, e1 el.SketchElement, e2 e
 	return constraint
 }
 
+// RemoveConstraint removes a constraint from the sketch
+func (g *SketchGraph) RemoveConstraint(c *constraint.Constraint) {
+	if _, ok := g.constraintAccessor.GetConstraint(c.GetID()); !ok {
+		return
+	}
+	utils.Logger.Debug().
+		Uint("constraint id", c.GetID()).
+		Msg("Removing constraint")
+	g.constraintAccessor.RemoveConstraint(c.GetID())
+	g.freeEdges.Remove(c.GetID())
+}
+
 func (g *SketchGraph) logConstraintsElements(level zerolog.Level) {
 	numElements := g.elementAccessor.Count()
 	numConstraints := g.constraintAccessor.Count()
@@ -42,28 +42,40 @@ func (g *SketchGraph) createClusters() {
 
 // findStartConstraint finds a constraint to start a cluster with. The strategy is
 // in order of precedence
-//  1. find a constraint where both elements are in other clusters, connecting
+//  1. find the lowest id constraint where both elements are fixed, so the origin and
+//     axes start the first cluster and other fixed elements join it.
+//  2. find a constraint where both elements are in other clusters, connecting
 //     those clusters with the cluster built from that constraint.
-//  2. find a constraint where one element is in another cluster in the hopes that
+//  3. find a constraint where one element is in another cluster in the hopes that
 //     a future third cluster will connect the existing one and the one about to
 //     be created.
 func (g *SketchGraph) findStartConstraint() uint {
 	constraints := make([]uint, 0)
+	fixedStart, hasFixedStart := uint(0), false
+	connectingStart, hasConnectingStart := uint(0), false
 	for _, constraintId := range g.freeEdges.Contents() {
 		constraint, ok := g.constraintAccessor.GetConstraint(constraintId)
 		if !ok {
 			continue
 		}
-		// If the constraint's elements are both fixed, use this as a start
+		// If the constraint's elements are both fixed, use this as a start. The lowest id is
+		// used so that the origin and axes start the first cluster and other fixed elements
+		// join it rather than starting clusters of their own.
 		if g.elementAccessor.IsFixed(constraint.Element1) && g.elementAccessor.IsFixed(constraint.Element2) {
-			return constraintId
+			if !hasFixedStart || constraintId < fixedStart {
+				fixedStart, hasFixedStart = constraintId, true
+			}
+			continue
 		}
 		// If we have a constraint where both elements are used, but the constraint
 		// is free, it means each of those elements are in a different cluster.
 		// Use this constraint as a start to tie those clusters together.
 		if g.usedNodes.Contains(constraint.Element1) &&
 			g.usedNodes.Contains(constraint.Element2) {
-			return constraintId
+			if !hasConnectingStart {
+				connectingStart, hasConnectingStart = constraintId, true
+			}
+			continue
 		}
 
 		if g.usedNodes.Contains(constraint.Element1) ||
@@ -72,6 +84,13 @@ func (g *SketchGraph) findStartConstraint() uint {
 		}
 	}
 
+	if hasFixedStart {
+		return fixedStart
+	}
+	if hasConnectingStart {
+		return connectingStart
+	}
+
 	// Check unused elements in constraints for highest constraint count
 	var retVal uint = 0
 	ccount := 0
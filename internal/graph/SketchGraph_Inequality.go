@@ -0,0 +1,202 @@
+package graph
+
+import (
+	"math/big"
+
+	"github.com/marcuswu/dlineate/internal/constraint"
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
+	"github.com/marcuswu/dlineate/internal/solver"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// inequalityAnchorWeight keeps elements near their graph solved positions while meeting inequalities
+const inequalityAnchorWeight = 0.000001
+
+// AddInequality adds a minimum or maximum distance or angle constraint to existing sketch elements.
+// Inequalities can't be solved constructively, so they are kept out of the graph and enforced by
+// the numeric solver once the graph has been solved.
+func (g *SketchGraph) AddInequality(t constraint.Type, bound constraint.Bound, e1 el.SketchElement, e2 el.SketchElement, value *big.Float) *constraint.Constraint {
+	utils.Logger.Debug().
+		Str("type", t.String()).
+		Str("bound", bound.String()).
+		Str("value", value.String()).
+		Uint("element 1", e1.GetID()).
+		Uint("element 2", e2.GetID()).
+		Msg("Adding inequality")
+	c := constraint.NewConstraint(uint(len(g.inequalities)), t, e1.GetID(), e2.GetID(), value, false)
+	c.Bound = bound
+	g.inequalities = append(g.inequalities, c)
+	return c
+}
+
+// IsInequalityActive returns whether an inequality is at its limit in the current solution
+func (g *SketchGraph) IsInequalityActive(c *constraint.Constraint) bool {
+	e1, ok1 := g.elementAccessor.GetElement(-1, c.Element1)
+	e2, ok2 := g.elementAccessor.GetElement(-1, c.Element2)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return c.IsAtBound(e1, e2)
+}
+
+// SolveInequalities checks the inequalities and loop constraints against the current solution. If any
+// are not met, the elements are moved by the numeric solver to meet them along with all other constraints.
+func (g *SketchGraph) SolveInequalities() solver.SolveState {
+	reflected := g.reflectSides()
+	unmet := 0
+	loops := 0
+	for _, c := range g.inequalities {
+		e1, _ := g.elementAccessor.GetElement(-1, c.Element1)
+		e2, _ := g.elementAccessor.GetElement(-1, c.Element2)
+		if !c.IsMet(e1, e2) {
+			unmet++
+		}
+	}
+	for _, c := range g.loops {
+		if !c.IsSide() {
+			loops++
+		}
+		if !g.IsLoopMet(c) {
+			unmet++
+		}
+	}
+	utils.Logger.Info().
+		Int("inequalities", len(g.inequalities)).
+		Int("loops", len(g.loops)).
+		Int("unmet", unmet).
+		Msg("Checked inequalities")
+	// Area and perimeter constraints remove freedom the graph can't account for, so a graph left unsolved
+	// may be solved numerically along with them. Reflected points may be the ends of lines which need
+	// to follow them.
+	if unmet == 0 && !reflected && (loops == 0 || g.state == solver.Solved) {
+		return solver.Solved
+	}
+
+	numericSolver := numeric.NewSolver()
+
+	// Add points before lines so segments share the points being solved. Points which aren't connected to
+	// any inequality or loop can't help meet them, so they are held where the graph solved them.
+	movable := g.connectedElements(g.inequalityElements())
+	elementIds := g.elementAccessor.IdSet().Contents()
+	lines := make([]*el.SketchLine, 0)
+	for _, eId := range elementIds {
+		e, _ := g.elementAccessor.GetElement(-1, eId)
+		if e.GetType() == el.Line {
+			lines = append(lines, g.currentLine(e.AsLine()))
+			continue
+		}
+		copied := el.CopySketchElement(e)
+		if !movable.Contains(eId) {
+			copied.SetFixed(true)
+		}
+		numericSolver.AddElement(copied)
+	}
+	for _, l := range lines {
+		numericSolver.AddElement(numeric.NewSegmentFromLine(l))
+	}
+
+	nextId := uint(0)
+	for _, cId := range g.constraintAccessor.IdSet().Contents() {
+		c, _ := g.constraintAccessor.GetConstraint(cId)
+		if cId >= nextId {
+			nextId = cId + 1
+		}
+		// Constraints between lines and their own points are intrinsic to segments
+		if g.isLineEndpointConstraint(c) {
+			continue
+		}
+		numericSolver.AddConstraint(c)
+	}
+	// Inequalities are numbered separately from the graph's constraints
+	for _, c := range g.inequalities {
+		copied := constraint.NewConstraint(nextId, c.Type, c.Element1, c.Element2, &c.Value, false)
+		copied.Bound = c.Bound
+		numericSolver.AddConstraint(copied)
+		nextId++
+	}
+	for _, c := range g.loops {
+		numericSolver.AddLoopConstraint(c)
+	}
+
+	// Move elements as little as possible to meet the inequalities
+	numericSolver.SetAnchorWeight(inequalityAnchorWeight)
+	if !numericSolver.Solve(utils.StandardCompare, utils.MaxNumericIterations) {
+		utils.Logger.Error().Msg("Numeric solver failed to meet inequalities and loop constraints")
+		return solver.NonConvergent
+	}
+
+	// Copy the solution back into the graph
+	for _, eId := range elementIds {
+		e, _ := g.elementAccessor.GetElement(-1, eId)
+		if e.IsFixed() || e.GetType() != el.Point {
+			continue
+		}
+		solved, ok := numericSolver.GetElement(eId)
+		if !ok {
+			continue
+		}
+		el.SetElementValues(e, el.ElementValues(solved))
+	}
+	for _, l := range lines {
+		if l.IsFixed() {
+			continue
+		}
+		e, _ := g.elementAccessor.GetElement(-1, l.GetID())
+		line := e.AsLine()
+		a, b, c := utils.BigFloatLineFromBigPoints(&l.Start.X, &l.Start.Y, &l.End.X, &l.End.Y)
+		updated := el.NewSketchLine(line.GetID(), a, b, c)
+		line.SetA(updated.GetA())
+		line.SetB(updated.GetB())
+		line.SetC(updated.GetC())
+		line.Start = l.Start
+		line.End = l.End
+	}
+
+	return solver.Solved
+}
+
+// inequalityElements returns the elements of the inequalities and loop constraints
+func (g *SketchGraph) inequalityElements() []uint {
+	eIds := make([]uint, 0)
+	for _, c := range g.inequalities {
+		eIds = append(eIds, c.Element1, c.Element2)
+	}
+	for _, c := range g.loops {
+		eIds = append(eIds, c.Points...)
+	}
+	return eIds
+}
+
+// currentLine returns a copy of a line referencing the graph's current start and end points
+func (g *SketchGraph) currentLine(l *el.SketchLine) *el.SketchLine {
+	line := el.CopySketchElement(l).AsLine()
+	if line.Start != nil {
+		if start, ok := g.elementAccessor.GetElement(-1, line.Start.GetID()); ok {
+			line.Start = start.AsPoint()
+		}
+	}
+	if line.End != nil {
+		if end, ok := g.elementAccessor.GetElement(-1, line.End.GetID()); ok {
+			line.End = end.AsPoint()
+		}
+	}
+	return line
+}
+
+func (g *SketchGraph) isLineEndpointConstraint(c *constraint.Constraint) bool {
+	for _, eId := range c.ElementIDs() {
+		e, ok := g.elementAccessor.GetElement(-1, eId)
+		if !ok || e.GetType() != el.Line {
+			continue
+		}
+		l := e.AsLine()
+		if l.Start == nil || l.End == nil {
+			continue
+		}
+		if c.HasElementID(l.Start.GetID()) || c.HasElementID(l.End.GetID()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/internal/numeric"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// AddLoopConstraint adds a constraint on the area or perimeter of the polygon through points in order.
+// Like inequalities, loop constraints relate too many elements to be solved constructively, so they are
+// kept out of the graph and enforced by the numeric solver once the graph has been solved.
+func (g *SketchGraph) AddLoopConstraint(measure numeric.LoopMeasure, points []el.SketchElement, value float64) *numeric.LoopConstraint {
+	ids := make([]uint, 0, len(points))
+	for _, p := range points {
+		ids = append(ids, p.GetID())
+	}
+	utils.Logger.Debug().
+		Str("measure", measure.String()).
+		Float64("value", value).
+		Uints("points", ids).
+		Msg("Adding loop constraint")
+	c := numeric.NewLoopConstraint(measure, ids, value)
+	g.loops = append(g.loops, c)
+	return c
+}
+
+// RemoveLoopConstraint removes a loop constraint from those enforced after the graph solve
+func (g *SketchGraph) RemoveLoopConstraint(c *numeric.LoopConstraint) {
+	for i, other := range g.loops {
+		if other == c {
+			g.loops = append(g.loops[:i], g.loops[i+1:]...)
+			return
+		}
+	}
+}
+
+// loopElements returns the current elements of a loop constraint
+func (g *SketchGraph) loopElements(c *numeric.LoopConstraint) ([]el.SketchElement, bool) {
+	elements := make([]el.SketchElement, 0, len(c.Points))
+	for _, eId := range c.Points {
+		e, ok := g.elementAccessor.GetElement(-1, eId)
+		if !ok {
+			return nil, false
+		}
+		if e.GetType() == el.Line {
+			e = g.currentLine(e.AsLine())
+		}
+		elements = append(elements, e)
+	}
+	return elements, true
+}
+
+// IsLoopMet returns whether a loop constraint is met by the current solution
+func (g *SketchGraph) IsLoopMet(c *numeric.LoopConstraint) bool {
+	elements, ok := g.loopElements(c)
+	return ok && c.IsMet(elements)
+}
+
+// reflectSides moves points the graph solve left on the wrong side of a side constraint to the other
+// side. The graph solver picks whichever solution is nearest a point's previous position, so a point
+// with two solutions, such as an intersection, may land on the other one when the sketch moves.
+// It returns whether any points were moved.
+func (g *SketchGraph) reflectSides() bool {
+	reflected := false
+	for _, c := range g.loops {
+		if !c.IsSide() || g.IsLoopMet(c) {
+			continue
+		}
+		elements, _ := g.loopElements(c)
+		p := elements[len(elements)-1]
+		if p.IsFixed() {
+			continue
+		}
+		utils.Logger.Debug().
+			Str("loop", c.String()).
+			Msg("Reflecting point to meet side constraint")
+		c.Reflect(elements)
+		reflected = true
+	}
+	return reflected
+}
+
+// Freedom returns the degrees of freedom left by the graph's constraints. Each free point or line has two
+// and each constraint involving one removes one.
+func (g *SketchGraph) Freedom() int {
+	return g.freedom(func(uint) bool { return true })
+}
+
+// LoopFreedom returns the degrees of freedom left in the elements connected to the points of area and
+// perimeter constraints, which those constraints may remove, followed by the freedom left in all others.
+func (g *SketchGraph) LoopFreedom() (int, int) {
+	points := make([]uint, 0)
+	for _, c := range g.loops {
+		if !c.IsSide() {
+			points = append(points, c.Points...)
+		}
+	}
+	connected := g.connectedElements(points)
+	return g.freedom(connected.Contains), g.freedom(func(eId uint) bool { return !connected.Contains(eId) })
+}
+
+// freedom returns the degrees of freedom left in the elements for which include returns true
+func (g *SketchGraph) freedom(include func(uint) bool) int {
+	freedom := 0
+	for _, eId := range g.elementAccessor.IdSet().Contents() {
+		if include(eId) && !g.elementAccessor.IsFixed(eId) {
+			freedom += 2
+		}
+	}
+	for _, cId := range g.constraintAccessor.IdSet().Contents() {
+		c, _ := g.constraintAccessor.GetConstraint(cId)
+		free1 := include(c.Element1) && !g.elementAccessor.IsFixed(c.Element1)
+		free2 := include(c.Element2) && !g.elementAccessor.IsFixed(c.Element2)
+		if free1 || free2 {
+			freedom--
+		}
+	}
+	return freedom
+}
+
+// connectedElements returns the free elements constrained to any of eIds, directly or through other free
+// elements. Fixed elements don't move, so they don't connect the elements constrained to them.
+func (g *SketchGraph) connectedElements(eIds []uint) *utils.Set {
+	connected := utils.NewSet()
+	next := make([]uint, 0, len(eIds))
+	for _, eId := range eIds {
+		if !g.elementAccessor.IsFixed(eId) && !connected.Contains(eId) {
+			connected.Add(eId)
+			next = append(next, eId)
+		}
+	}
+	constraints := g.constraintAccessor.IdSet().Contents()
+	for len(next) > 0 {
+		eId := next[0]
+		next = next[1:]
+		for _, cId := range constraints {
+			c, _ := g.constraintAccessor.GetConstraint(cId)
+			other, ok := c.Other(eId)
+			if !ok || g.elementAccessor.IsFixed(other) || connected.Contains(other) {
+				continue
+			}
+			connected.Add(other)
+			next = append(next, other)
+		}
+	}
+	return connected
+}
@@ -100,6 +100,66 @@ func TestFindStartConstraint(t *testing.T) {
 
 	start = sketch.findStartConstraint()
 	assert.Contains(t, []uint{1, 2}, start)
+
+	// The lowest id constraint between fixed elements starts the first cluster
+	sketch = NewSketch()
+	e1 = sketch.AddOrigin(big.NewFloat(0), big.NewFloat(0))
+	e2 = sketch.AddPoint(big.NewFloat(1), big.NewFloat(0))
+	e3 = sketch.AddPoint(big.NewFloat(2), big.NewFloat(0))
+	sketch.MakeFixed(e2)
+	sketch.MakeFixed(e3)
+	c1 := sketch.AddConstraint(constraint.Distance, e1, e2, big.NewFloat(1))
+	sketch.AddConstraint(constraint.Distance, e2, e3, big.NewFloat(1))
+	sketch.AddConstraint(constraint.Distance, e3, e1, big.NewFloat(2))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, c1.GetID(), sketch.findStartConstraint(), "First fixed constraint starts the cluster")
+	}
+}
+
+func TestSolveWithFixedElements(t *testing.T) {
+	// Fixed elements pinned to the axes join the cluster started from the origin and axes. Starting from
+	// whichever fixed constraint came first in the unordered free constraints could start a cluster from
+	// the fixed line and its end points, which can't be merged with the others, so the solve is repeated.
+	for i := 0; i < 20; i++ {
+		s := NewSketch()
+		origin := s.AddOrigin(big.NewFloat(0), big.NewFloat(0))
+		xAxis := s.AddAxis(big.NewFloat(0), big.NewFloat(-1), big.NewFloat(0))
+		yAxis := s.AddAxis(big.NewFloat(1), big.NewFloat(0), big.NewFloat(0))
+		s.AddConstraint(constraint.Angle, xAxis, yAxis, big.NewFloat(math.Pi/2))
+		s.AddConstraint(constraint.Distance, origin, xAxis, big.NewFloat(0))
+		s.AddConstraint(constraint.Distance, origin, yAxis, big.NewFloat(0))
+
+		// A fixed line with its end points pinned to the axes
+		p1 := s.AddPoint(big.NewFloat(0), big.NewFloat(1))
+		p2 := s.AddPoint(big.NewFloat(4), big.NewFloat(1))
+		l1 := s.AddLine(big.NewFloat(0), big.NewFloat(1), big.NewFloat(-1))
+		l1.AsLine().Start = p1.AsPoint()
+		l1.AsLine().End = p2.AsPoint()
+		for _, e := range []el.SketchElement{p1, p2, l1} {
+			s.MakeFixed(e)
+		}
+		s.AddConstraint(constraint.Distance, p1, yAxis, big.NewFloat(0))
+		s.AddConstraint(constraint.Distance, p1, xAxis, big.NewFloat(1))
+		s.AddConstraint(constraint.Distance, p2, yAxis, big.NewFloat(4))
+		s.AddConstraint(constraint.Distance, p2, xAxis, big.NewFloat(1))
+		s.AddConstraint(constraint.Distance, l1, p1, big.NewFloat(0))
+		s.AddConstraint(constraint.Distance, l1, p2, big.NewFloat(0))
+
+		p3 := s.AddPoint(big.NewFloat(2.2), big.NewFloat(1.1))
+		s.AddConstraint(constraint.Distance, p3, l1, big.NewFloat(0))
+		s.AddConstraint(constraint.Distance, p3, p1, big.NewFloat(2))
+
+		s.ResetClusters()
+		s.BuildClusters()
+		state := s.Solve()
+
+		assert.Equal(t, solver.Solved, state, "Graph with fixed elements should be solved")
+		solved, _ := s.GetElement(p3.GetID())
+		x, _ := solved.AsPoint().GetX().Float64()
+		y, _ := solved.AsPoint().GetY().Float64()
+		assert.InDelta(t, 2, x, utils.StandardCompare, "Free point is solved from the fixed point")
+		assert.InDelta(t, 1, y, utils.StandardCompare, "Free point is solved from the fixed point")
+	}
 }
 
 func TestFindConstraints(t *testing.T) {
@@ -235,10 +295,23 @@ func TestCreateBuildResetClusters(t *testing.T) {
 	assert.Equal(t, 3, sketch.clusters[1].elements.Count(), "cluster 1 should have 2 element, 1 constraints")
 	assert.Equal(t, 3, len(sketch.clusters[1].constraints), "cluster 1 should have 2 element, 1 constraints")
 
+	shared := make(map[uint]bool)
+	for _, e := range []el.SketchElement{e0, e1, e2, e3, e4, e5, e6} {
+		shared[e.GetID()] = sketch.elementAccessor.IsShared(e.GetID())
+	}
+
 	sketch.ResetClusters()
 	assert.Equal(t, 0, len(sketch.clusters), "Should have 0 clusters")
+	for _, e := range []el.SketchElement{e0, e1, e2, e3, e4, e5, e6} {
+		_, ok := sketch.elementAccessor.Cluster(e.GetID())
+		assert.False(t, ok, "Elements should not be in a cluster after a reset")
+		assert.False(t, sketch.elementAccessor.IsShared(e.GetID()), "Elements should not be shared after a reset")
+	}
 
 	sketch.BuildClusters()
+	for _, e := range []el.SketchElement{e0, e1, e2, e3, e4, e5, e6} {
+		assert.Equal(t, shared[e.GetID()], sketch.elementAccessor.IsShared(e.GetID()), "Rebuilt clusters should share the same elements")
+	}
 
 	assert.Equal(t, 2, len(sketch.clusters), "Should have 2 clusters")
 	assert.Equal(t, 5, sketch.clusters[0].elements.Count(), "cluster 0 should have 6 element, 9 constraints")
@@ -351,6 +424,26 @@ func TestSolve(t *testing.T) {
 	assert.Equal(t, solver.NonConvergent, state, "Graph should be non-convergent")
 }
 
+func TestRemoveConstraint(t *testing.T) {
+	s := NewSketch()
+	p1 := s.AddPoint(big.NewFloat(0), big.NewFloat(0))
+	p2 := s.AddPoint(big.NewFloat(1), big.NewFloat(0))
+	p3 := s.AddPoint(big.NewFloat(0), big.NewFloat(1))
+	c1 := s.AddConstraint(constraint.Distance, p1, p2, big.NewFloat(1))
+	c2 := s.AddConstraint(constraint.Distance, p1, p3, big.NewFloat(1))
+
+	s.RemoveConstraint(c1)
+	_, ok := s.GetConstraint(c1.GetID())
+	assert.False(t, ok, "Removed constraint is not in the sketch")
+	assert.False(t, s.freeEdges.Contains(c1.GetID()), "Removed constraint is not a free edge")
+	assert.Equal(t, 0, len(s.constraintAccessor.ConstraintsForElement(p2.GetID())), "Removed constraint is not on its elements")
+	s.RemoveConstraint(c1)
+
+	c3 := s.AddConstraint(constraint.Distance, p2, p3, big.NewFloat(1))
+	assert.NotEqual(t, c2.GetID(), c3.GetID(), "Constraint ids are not reused after removing a constraint")
+	assert.NotEqual(t, c1.GetID(), c3.GetID(), "Constraint ids are not reused after removing a constraint")
+}
+
 func TestFindMergeForCluster(t *testing.T) {
 	s := NewSketch()
 	origin := s.AddOrigin(big.NewFloat(0), big.NewFloat(0))
@@ -0,0 +1,172 @@
+package numeric
+
+import (
+	"fmt"
+	"math"
+
+	el "github.com/marcuswu/dlineate/internal/element"
+	"github.com/marcuswu/dlineate/utils"
+)
+
+// LoopMeasure is a property of a closed loop of points
+type LoopMeasure uint
+
+// LoopMeasure constants
+const (
+	LoopArea LoopMeasure = iota
+	LoopPerimeter
+	// LoopOrientation is the signed area of the loop, positive when it runs counter-clockwise
+	LoopOrientation
+	// LoopProjection is the distance along a line from one point to another. Its points are the line
+	// followed by the two points.
+	LoopProjection
+)
+
+func (m LoopMeasure) String() string {
+	switch m {
+	case LoopArea:
+		return "Area"
+	case LoopPerimeter:
+		return "Perimeter"
+	case LoopOrientation:
+		return "Orientation"
+	case LoopProjection:
+		return "Projection"
+	default:
+		return fmt.Sprintf("%d", int(m))
+	}
+}
+
+// LoopConstraint constrains the area or perimeter of the polygon through a closed loop of points.
+// It involves any number of points, so it is solved numerically as an additional error term
+// rather than by the graph solver.
+// Orientation and projection constraints are sides rather than values. Their value is 1 or -1 and they
+// are met when the measure has the same sign, which keeps points on one side of a line without removing
+// any freedom.
+type LoopConstraint struct {
+	Measure LoopMeasure
+	Points  []uint
+	Value   float64
+}
+
+// NewLoopConstraint creates a constraint on the polygon through the points in order
+func NewLoopConstraint(measure LoopMeasure, points []uint, value float64) *LoopConstraint {
+	return &LoopConstraint{Measure: measure, Points: points, Value: value}
+}
+
+// Current returns the current area or perimeter of the polygon through the points.
+// Areas are absolute so the loop may run in either direction, while orientations and projections are signed.
+func (c *LoopConstraint) Current(points []el.SketchElement) float64 {
+	if c.Measure == LoopProjection {
+		dx, dy := lineDirection(points[0])
+		x1, y1 := pointValues(points[1])
+		x2, y2 := pointValues(points[2])
+		return (((x2 - x1) * dx) + ((y2 - y1) * dy)) / math.Hypot(dx, dy)
+	}
+	total := 0.0
+	for i := range points {
+		x1, y1 := pointValues(points[i])
+		x2, y2 := pointValues(points[(i+1)%len(points)])
+		if c.Measure == LoopPerimeter {
+			total += math.Hypot(x2-x1, y2-y1)
+			continue
+		}
+		// Shoelace formula
+		total += (x1 * y2) - (x2 * y1)
+	}
+	switch c.Measure {
+	case LoopArea:
+		return math.Abs(total / 2)
+	case LoopOrientation:
+		return total / 2
+	}
+	return total
+}
+
+// IsSide returns whether the constraint keeps a point to one side rather than constraining a value
+func (c *LoopConstraint) IsSide() bool {
+	return c.Measure == LoopOrientation || c.Measure == LoopProjection
+}
+
+// Error returns the squared difference between the measured and desired values. Sides are a penalty
+// which is 0 anywhere the measure has the desired sign.
+func (c *LoopConstraint) Error(points []el.SketchElement) float64 {
+	diff := c.Current(points) - c.Value
+	if c.IsSide() {
+		diff = math.Min(c.Current(points)*c.Value, 0)
+	}
+	return diff * diff
+}
+
+// IsMet returns whether the polygon through the points has the desired value
+func (c *LoopConstraint) IsMet(points []el.SketchElement) bool {
+	if c.IsSide() {
+		return utils.StandardFloatCompare(math.Min(c.Current(points)*c.Value, 0), 0) == 0
+	}
+	return utils.StandardFloatCompare(c.Current(points), c.Value) == 0
+}
+
+// Reflect moves the last point of a side constraint to the other side. An orientation's point is
+// reflected across the line through the first two points and a projection's point across the
+// perpendicular to the line through the second point.
+// Where the point is the intersection of two circles, or of a circle and a line, this moves it to the
+// other intersection.
+func (c *LoopConstraint) Reflect(points []el.SketchElement) {
+	if !c.IsSide() {
+		return
+	}
+	p := points[len(points)-1]
+	x, y := pointValues(p)
+	ox, oy := pointValues(points[1])
+	var nx, ny float64
+	if c.Measure == LoopOrientation {
+		// The normal to the line through the first two points
+		x1, y1 := pointValues(points[0])
+		nx, ny = y1-oy, ox-x1
+	} else {
+		nx, ny = lineDirection(points[0])
+	}
+	length := math.Hypot(nx, ny)
+	if length == 0 {
+		return
+	}
+	nx, ny = nx/length, ny/length
+	d := ((x - ox) * nx) + ((y - oy) * ny)
+	el.SetElementValues(p, []float64{x - (2 * d * nx), y - (2 * d * ny)})
+}
+
+// ReplacePoint updates the loop when a point is combined with another
+func (c *LoopConstraint) ReplacePoint(from uint, to uint) {
+	for i, p := range c.Points {
+		if p == from {
+			c.Points[i] = to
+		}
+	}
+}
+
+func (c *LoopConstraint) String() string {
+	return fmt.Sprintf("Loop %v of points %v = %f", c.Measure, c.Points, c.Value)
+}
+
+// lineDirection returns the direction of a line from its start to its end
+func lineDirection(e el.SketchElement) (float64, float64) {
+	var start, end *el.SketchPoint
+	if segment, ok := e.(*Segment); ok {
+		start, end = segment.start, segment.end
+	} else if line := e.AsLine(); line.Start != nil && line.End != nil {
+		start, end = line.Start, line.End
+	} else {
+		direction := line.Direction()
+		dx, _ := direction.X.Float64()
+		dy, _ := direction.Y.Float64()
+		return dx, dy
+	}
+	x1, y1 := pointValues(start)
+	x2, y2 := pointValues(end)
+	return x2 - x1, y2 - y1
+}
+
+func pointValues(e el.SketchElement) (float64, float64) {
+	values := el.ElementValues(e)
+	return values[0], values[1]
+}
@@ -16,6 +16,8 @@ type Solver struct {
 	Constraints   accessors.ConstraintAccessor
 	fixedElements *utils.Set
 	valueOrder    []uint
+	anchorWeight  float64
+	loops         []*LoopConstraint
 }
 
 func NewSolver() *Solver {
@@ -58,6 +60,13 @@ func (s *Solver) AddElement(e el.SketchElement) {
 	}
 }
 
+// SetAnchorWeight adds a term to the solver's error pulling each free value towards its starting value.
+// With a small weight, constraints which are met across a range of values (such as inequalities)
+// are met with the least movement from the starting values.
+func (s *Solver) SetAnchorWeight(weight float64) {
+	s.anchorWeight = weight
+}
+
 func (s *Solver) GetElement(id uint) (el.SketchElement, bool) {
 	return s.Elements.GetElement(-1, id)
 }
@@ -92,6 +101,30 @@ func (s *Solver) AddConstraint(c *constraint.Constraint) {
 	s.addValueOrder(c.Element2)
 }
 
+// AddLoopConstraint adds a constraint on the area or perimeter of a loop of points to the solver's error
+func (s *Solver) AddLoopConstraint(c *LoopConstraint) {
+	s.loops = append(s.loops, c)
+	for _, pId := range c.Points {
+		s.addValueOrder(pId)
+	}
+}
+
+// loopPoints returns the points of a loop constraint
+func (s *Solver) loopPoints(c *LoopConstraint) []el.SketchElement {
+	points := make([]el.SketchElement, 0, len(c.Points))
+	for _, pId := range c.Points {
+		p, ok := s.Elements.GetElement(-1, pId)
+		if !ok {
+			utils.Logger.Error().
+				Uint("Element id", pId).
+				Msg("Failed to find loop point")
+			continue
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
 func (s *Solver) FreeValues() []float64 {
 	freeValues := make([]float64, 0, len(s.valueOrder)*2)
 	for _, eId := range s.valueOrder {
@@ -141,14 +174,26 @@ func (s *Solver) Error() float64 {
 		constraintError := constraint.Error(e1, e2)
 		totalError += constraintError
 	}
+	for _, loop := range s.loops {
+		totalError += loop.Error(s.loopPoints(loop))
+	}
 	return totalError
 }
 
 func (s *Solver) Solve(tolerance float64, maxIterations int) bool {
+	initialValues := s.FreeValues()
+	if len(initialValues) == 0 {
+		utils.Logger.Debug().
+			Msg("Numeric solver: no free values to solve")
+		return false
+	}
+	start := make([]float64, len(initialValues))
+	copy(start, initialValues)
+
 	problem := optimize.Problem{
 		Func: func(x []float64) float64 {
 			s.Update(x)
-			return s.Error()
+			return s.Error() + s.anchorError(start, x)
 		},
 	}
 
@@ -156,12 +201,6 @@ func (s *Solver) Solve(tolerance float64, maxIterations int) bool {
 		MajorIterations: maxIterations,
 	}
 
-	initialValues := s.FreeValues()
-	if len(initialValues) == 0 {
-		utils.Logger.Debug().
-			Msg("Numeric solver: no free values to solve")
-		return false
-	}
 	solution, err := optimize.Minimize(problem, initialValues, &settings, nil)
 	if err != nil {
 		utils.Logger.Debug().Err(err).
@@ -209,8 +248,28 @@ func (s *Solver) Solve(tolerance float64, maxIterations int) bool {
 				Float64("error", constraintError).
 				Msg("Final constraint error")
 		}
+		for _, loop := range s.loops {
+			utils.Logger.Info().
+				Str("loop", loop.String()).
+				Float64("error", loop.Error(s.loopPoints(loop))).
+				Msg("Final loop constraint error")
+		}
 	}
 
-	solved := solution.F <= tolerance
+	// Measure the solution without the anchor term
+	s.Update(solution.X)
+	solved := s.Error() <= tolerance
 	return solved
 }
+
+func (s *Solver) anchorError(start []float64, x []float64) float64 {
+	if s.anchorWeight == 0 {
+		return 0
+	}
+	total := 0.0
+	for i := range x {
+		diff := x[i] - start[i]
+		total += diff * diff
+	}
+	return total * s.anchorWeight
+}
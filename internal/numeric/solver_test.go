@@ -238,3 +238,69 @@ func TestSolveModifiedRectangle(t *testing.T) {
 		t.Errorf("Could not solve with a good error margin")
 	}
 }
+
+func TestSolveLoop(t *testing.T) {
+	solver := NewSolver()
+	p1 := addPoint(solver, 0, 0, true)
+	p2 := addPoint(solver, 4, 0, true)
+	p3 := addPoint(solver, 2, 1, false)
+	points := []el.SketchElement{p1, p2, p3}
+
+	perimeter := NewLoopConstraint(LoopPerimeter, []uint{p1.GetID(), p2.GetID(), p3.GetID()}, 0)
+	if math.Abs(perimeter.Current(points)-(4+(2*math.Sqrt(5)))) > utils.StandardCompare {
+		t.Errorf("Expected triangle perimeter %f, got %f", 4+(2*math.Sqrt(5)), perimeter.Current(points))
+	}
+
+	area := NewLoopConstraint(LoopArea, []uint{p3.GetID(), p2.GetID(), p1.GetID()}, 6)
+	if math.Abs(area.Current(points)-2) > utils.StandardCompare {
+		t.Errorf("Expected triangle area 2 in either direction, got %f", area.Current(points))
+	}
+	solver.AddLoopConstraint(area)
+	if !solver.Solve(utils.StandardCompare, utils.MaxNumericIterations) {
+		t.Errorf("Could not solve triangle area")
+	}
+	solved, _ := solver.GetElement(p3.GetID())
+	if !area.IsMet([]el.SketchElement{p1, p2, solved}) {
+		t.Errorf("Expected triangle area 6, got %f", area.Current([]el.SketchElement{p1, p2, solved}))
+	}
+
+	area.ReplacePoint(p3.GetID(), 10)
+	if area.Points[0] != 10 {
+		t.Errorf("Expected replaced point 10, got %d", area.Points[0])
+	}
+}
+
+func TestLoopSides(t *testing.T) {
+	solver := NewSolver()
+	p1 := addPoint(solver, 0, 0, true)
+	p2 := addPoint(solver, 4, 0, true)
+	p3 := addPoint(solver, 1, -1, false)
+	line := addLine(solver, p1, p2)
+	center := addPoint(solver, 2, 3, true)
+	points := []el.SketchElement{p1, p2, p3}
+
+	orientation := NewLoopConstraint(LoopOrientation, []uint{p1.GetID(), p2.GetID(), p3.GetID()}, 1)
+	if orientation.IsMet(points) || orientation.Error(points) == 0 {
+		t.Errorf("Expected clockwise triangle to be on the wrong side")
+	}
+	orientation.Reflect(points)
+	if values := el.ElementValues(p3); math.Abs(values[0]-1) > utils.StandardCompare || math.Abs(values[1]-1) > utils.StandardCompare {
+		t.Errorf("Expected point reflected to [1, 1], got %v", values)
+	}
+	if !orientation.IsMet(points) || orientation.Error(points) != 0 {
+		t.Errorf("Expected counter-clockwise triangle to be on the side, got %f", orientation.Current(points))
+	}
+
+	points = []el.SketchElement{line, center, p3}
+	projection := NewLoopConstraint(LoopProjection, []uint{line.GetID(), center.GetID(), p3.GetID()}, 1)
+	if projection.IsMet(points) {
+		t.Errorf("Expected point behind the center to be on the wrong side")
+	}
+	projection.Reflect(points)
+	if values := el.ElementValues(p3); math.Abs(values[0]-3) > utils.StandardCompare || math.Abs(values[1]-1) > utils.StandardCompare {
+		t.Errorf("Expected point reflected to [3, 1], got %v", values)
+	}
+	if !projection.IsMet(points) || math.Abs(projection.Current(points)-1) > utils.StandardCompare {
+		t.Errorf("Expected point 1 ahead of the center, got %f", projection.Current(points))
+	}
+}
@@ -72,7 +72,7 @@ func LineResult(cluster int, ea accessors.ElementAccessor, c1 *constraint.Constr
 		if !ok {
 			return line, NonConvergent
 		}
-		if !c1.IsMet(line, c1Other) || !c2.IsMet(line, c2Other) {
+		if !isMetWith(c1, line, c1Other) || !isMetWith(c2, line, c2Other) {
 			return line, NonConvergent
 		}
 		return line, Solved
@@ -107,6 +107,15 @@ func LineResult(cluster int, ea accessors.ElementAccessor, c1 *constraint.Constr
 	return line, solveState
 }
 
+// isMetWith checks whether a constraint is met, passing the elements in the order of the constraint
+// since angle constraints are signed
+func isMetWith(c *constraint.Constraint, e el.SketchElement, other el.SketchElement) bool {
+	if c.Element1 != e.GetID() {
+		return c.IsMet(other, e)
+	}
+	return c.IsMet(e, other)
+}
+
 // MoveLineToPoint solves a constraint between a line and a point where the line needs to move
 func MoveLineToPoint(ea accessors.ElementAccessor, c *constraint.Constraint) SolveState {
 	if c.Type != constraint.Distance {
@@ -443,6 +452,8 @@ func LineFromPointLine(cluster int, ea accessors.ElementAccessor, c1 *constraint
 }
 
 // SolveAngleConstraint solve an angle constraint between two lines
+// Angle constraints are signed: the counter-clockwise angle from Element1 to Element2 must be the
+// constraint value. The line e is rotated to satisfy the constraint, keeping the other line in place.
 func SolveAngleConstraint(cluster int, ea accessors.ElementAccessor, c *constraint.Constraint, e uint) (*el.SketchLine, SolveState) {
 	if c.Type != constraint.Angle {
 		utils.Logger.Error().
@@ -461,48 +472,29 @@ func SolveAngleConstraint(cluster int, ea accessors.ElementAccessor, c *constrai
 		return nil, NonConvergent
 	}
 	l2 := element.(*el.SketchLine)
-	var negDesired big.Float
-	desired := c.Value
-	negDesired.SetPrec(utils.FloatPrecision).Neg(&desired)
-	if l1.GetID() == e {
-		l1, l2 = l2, l1
-	}
-
-	var rotate1, rotate2, reverseRotate1, reverseRotate2 big.Float
-	angle1 := l2.AngleToLine(l1)
-	rotate1.Add(angle1, &desired)
-	rotate2.Add(&desired, angle1)
-	reverseRotate1.Sub(angle1, &desired)
-	reverseRotate2.Sub(&desired, angle1)
+	desired := c.GetValue()
 
-	lines := []*el.SketchLine{
-		l2.Rotated(&rotate1),
-		l2.Rotated(&rotate2),
-		l2.Rotated(&reverseRotate1),
-		l2.Rotated(&reverseRotate2),
+	// Rotating the solved line by its angle to the reference line aligns it with the reference.
+	// From there, rotate by the desired angle in the direction the constraint specifies.
+	reference, solveLine := l1, l2
+	var rotation big.Float
+	rotation.SetPrec(utils.FloatPrecision)
+	if l1.GetID() == e {
+		reference, solveLine = l2, l1
+		rotation.Sub(solveLine.AngleToLine(reference), desired)
+	} else {
+		rotation.Add(solveLine.AngleToLine(reference), desired)
 	}
 
-	var newLine *el.SketchLine = nil
-	// Find the option that is closest to the original line and fits the desired angle
-	for _, line := range lines {
-		if newLine == nil {
-			newLine = line
-			continue
-		}
-		var angle1, angle2 big.Float
-		angle1.SetPrec(utils.FloatPrecision).Copy(line.AngleToLine(l1))
-		if utils.StandardBigFloatCompare(&angle1, &desired) != 0 && utils.StandardBigFloatCompare(&angle1, &negDesired) != 0 {
-			continue
-		}
-		angle1.SetPrec(utils.FloatPrecision).Abs(line.AngleToLine(l2))
-		angle2.SetPrec(utils.FloatPrecision).Abs(newLine.AngleToLine(l2))
-		if angle1.Cmp(&angle2) < 0 {
-			newLine = line
-		}
+	newLine := solveLine.Rotated(&rotation)
+	first, second := reference, newLine
+	if l1.GetID() == e {
+		first, second = newLine, reference
 	}
 
-	angle1.SetPrec(utils.FloatPrecision).Copy(newLine.AngleToLine(l1))
-	if utils.StandardBigFloatCompare(angle1, &desired) != 0 && utils.StandardBigFloatCompare(angle1, &negDesired) != 0 {
+	current, _ := first.AngleToLine(second).Float64()
+	desiredValue, _ := desired.Float64()
+	if utils.StandardAngleCompare(current, desiredValue) != 0 {
 		return nil, NonConvergent
 	}
 	c.Solved = true
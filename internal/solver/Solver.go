@@ -39,6 +39,9 @@ func SolveConstraints(cluster int, ea accessors.ElementAccessor, c1 *constraint.
 	uniqueElements.Add(c1.Element2)
 	uniqueElements.Add(c2.Element1)
 	uniqueElements.Add(c2.Element2)
+	if uniqueElements.Count() == 2 && IsPointOnPoint(c1) && IsPointOnPoint(c2) {
+		return solvePointOnPoint(cluster, ea, c1, c2, solveFor)
+	}
 	if uniqueElements.Count() != 3 {
 		return OverConstrained
 	}
@@ -50,6 +53,28 @@ func SolveConstraints(cluster int, ea accessors.ElementAccessor, c1 *constraint.
 	return SolveForLine(cluster, ea, c1, c2)
 }
 
+// IsPointOnPoint returns whether a constraint is no distance between two points. Two of them between the same
+// points remove both degrees of freedom of one point by placing it on the other.
+func IsPointOnPoint(c *constraint.Constraint) bool {
+	return c.Type == constraint.Distance && utils.StandardBigFloatCompare(c.GetValue(), big.NewFloat(0)) == 0
+}
+
+// solvePointOnPoint moves solveFor onto the other point of two constraints placing one point on another
+func solvePointOnPoint(cluster int, ea accessors.ElementAccessor, c1 *constraint.Constraint, c2 *constraint.Constraint, solveFor el.SketchElement) SolveState {
+	otherId, _ := c1.Other(solveFor.GetID())
+	other, ok := ea.GetElement(cluster, otherId)
+	current, found := ea.GetElement(cluster, solveFor.GetID())
+	if !ok || !found || current.GetType() != el.Point || other.GetType() != el.Point {
+		return OverConstrained
+	}
+	p := current.AsPoint()
+	p.X = other.AsPoint().X
+	p.Y = other.AsPoint().Y
+	c1.Solved = true
+	c2.Solved = true
+	return Solved
+}
+
 func ConstraintResult(cluster int, ea accessors.ElementAccessor, c1 *constraint.Constraint, c2 *constraint.Constraint, solveFor el.SketchElement) (el.SketchElement, SolveState) {
 	if solveFor.GetType() == el.Point {
 		return PointResult(cluster, ea, c1, c2)
@@ -404,7 +429,12 @@ func PointFromPointLine(cluster int, ea accessors.ElementAccessor, c1 *constrain
 }
 
 func pointFromLineLine(l1 *el.SketchLine, l2 *el.SketchLine, p3 *el.SketchPoint, line1Dist *big.Float, line2Dist *big.Float) (*el.SketchPoint, SolveState) {
-	sameSlope := utils.StandardBigFloatCompare(l1.GetA(), l2.GetA()) == 0 && utils.StandardBigFloatCompare(l1.GetB(), l2.GetB()) == 0
+	var negA, negB big.Float
+	negA.Neg(l2.GetA())
+	negB.Neg(l2.GetB())
+	// Parallel lines may have normals in opposite directions
+	sameSlope := (utils.StandardBigFloatCompare(l1.GetA(), l2.GetA()) == 0 && utils.StandardBigFloatCompare(l1.GetB(), l2.GetB()) == 0) ||
+		(utils.StandardBigFloatCompare(l1.GetA(), &negA) == 0 && utils.StandardBigFloatCompare(l1.GetB(), &negB) == 0)
 	// If l1 and l2 are parallel, and the distance between the lines isn't line1Dist + line2Dist, we can't solve
 	distanceBetween := l1.DistanceTo(l2)
 	var combinedDistances big.Float
@@ -424,6 +454,13 @@ func pointFromLineLine(l1 *el.SketchLine, l2 *el.SketchLine, p3 *el.SketchPoint,
 		var scale, x, y big.Float
 		translate := l1.VectorTo(p3)
 		scale.Sub(p3.DistanceTo(l1), line1Dist)
+		// A point on the line has no direction to move in. It is already solved if it should be on the line.
+		if translate.Magnitude().Sign() == 0 {
+			if line1Dist.Sign() != 0 {
+				return nil, NonConvergent
+			}
+			return el.NewSketchPoint(p3.GetID(), p3.GetX(), p3.GetY()), Solved
+		}
 		scale.Quo(&scale, translate.Magnitude())
 		translate.Scaled(&scale)
 		x.Add(p3.GetX(), &translate.X)
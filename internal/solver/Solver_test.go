@@ -390,6 +390,24 @@ func TestPointFromLineLine(t *testing.T) {
 			el.NewSketchPoint(2, big.NewFloat(0.7), big.NewFloat(1.8)),
 			Solved,
 		},
+		{
+			"Test Parallel Opposite Normals",
+			el.NewSketchLine(0, big.NewFloat(0), big.NewFloat(1), big.NewFloat(-3)),
+			big.NewFloat(1.0),
+			el.NewSketchLine(1, big.NewFloat(0), big.NewFloat(-1), big.NewFloat(0)),
+			big.NewFloat(2.0),
+			el.NewSketchPoint(2, big.NewFloat(0.7), big.NewFloat(1.8)),
+			Solved,
+		},
+		{
+			"Test Same Line",
+			el.NewSketchLine(0, big.NewFloat(0), big.NewFloat(1), big.NewFloat(0)),
+			big.NewFloat(0.0),
+			el.NewSketchLine(1, big.NewFloat(0), big.NewFloat(-1), big.NewFloat(0)),
+			big.NewFloat(0.0),
+			el.NewSketchPoint(2, big.NewFloat(2), big.NewFloat(0)),
+			Solved,
+		},
 		{
 			"Test Intersect 1",
 			el.NewSketchLine(0, big.NewFloat(1), big.NewFloat(1), big.NewFloat(-1)),
@@ -585,10 +603,16 @@ func TestSolveConstraints(t *testing.T) {
 	c1 := constraint.NewConstraint(1, constraint.Distance, 1, 0, big.NewFloat(0), false)
 	c2 := constraint.NewConstraint(2, constraint.Angle, 3, 4, big.NewFloat((70.0/180.0)*math.Pi), false)
 	c3 := constraint.NewConstraint(3, constraint.Distance, 5, 4, big.NewFloat(1), false)
+	c4 := constraint.NewConstraint(4, constraint.Distance, 5, 2, big.NewFloat(0), false)
+	c5 := constraint.NewConstraint(5, constraint.Distance, 5, 2, big.NewFloat(0), false)
+	c6 := constraint.NewConstraint(6, constraint.Distance, 4, 2, big.NewFloat(0), false)
 	ca.AddConstraint(c0)
 	ca.AddConstraint(c1)
 	ca.AddConstraint(c2)
 	ca.AddConstraint(c3)
+	ca.AddConstraint(c4)
+	ca.AddConstraint(c5)
+	ca.AddConstraint(c6)
 	tests := []struct {
 		name     string
 		c1       *constraint.Constraint
@@ -598,6 +622,7 @@ func TestSolveConstraints(t *testing.T) {
 	}{
 		{"Test Solve For Point", c0, c1, el.NewSketchPoint(1, big.NewFloat(0.1), big.NewFloat(1)), Solved},
 		{"Test Solve For Line", c2, c3, el.NewSketchLine(4, big.NewFloat(0.151089), big.NewFloat(0.988520), big.NewFloat(-0.139610)), Solved},
+		{"Test Solve For Point On Point", c4, c5, el.NewSketchPoint(5, big.NewFloat(1), big.NewFloat(1)), Solved},
 	}
 	for _, tt := range tests {
 		solved := SolveConstraints(-1, ea, tt.c1, tt.c2, tt.solveFor)
@@ -605,6 +630,9 @@ func TestSolveConstraints(t *testing.T) {
 		assert.True(t, ca.IsMet(tt.c1.GetID(), -1, ea), tt.name)
 		assert.True(t, ca.IsMet(tt.c2.GetID(), -1, ea), tt.name)
 	}
+
+	// A line can't be placed on a point
+	assert.Equal(t, OverConstrained, SolveConstraints(-1, ea, c6, c6, el.NewSketchLine(4, big.NewFloat(0), big.NewFloat(1), big.NewFloat(0))))
 }
 
 func TestSolveDistanceConstraint(t *testing.T) {
@@ -679,10 +707,16 @@ func TestPointResult(t *testing.T) {
 	c1 := constraint.NewConstraint(1, constraint.Distance, 2, 1, big.NewFloat(1), false)
 	c2 := constraint.NewConstraint(2, constraint.Distance, 5, 3, big.NewFloat(1), false)
 	c3 := constraint.NewConstraint(3, constraint.Distance, 5, 4, big.NewFloat(1), false)
+	c4 := constraint.NewConstraint(4, constraint.Distance, 5, 2, big.NewFloat(0), false)
+	c5 := constraint.NewConstraint(5, constraint.Distance, 5, 2, big.NewFloat(0), false)
+	c6 := constraint.NewConstraint(6, constraint.Distance, 4, 2, big.NewFloat(0), false)
 	ca.AddConstraint(c0)
 	ca.AddConstraint(c1)
 	ca.AddConstraint(c2)
 	ca.AddConstraint(c3)
+	ca.AddConstraint(c4)
+	ca.AddConstraint(c5)
+	ca.AddConstraint(c6)
 	tests := []struct {
 		name    string
 		c1      *constraint.Constraint
@@ -748,10 +782,16 @@ func TestConstraintResult(t *testing.T) {
 	c1 := constraint.NewConstraint(1, constraint.Distance, 2, 1, big.NewFloat(1), false)
 	c2 := constraint.NewConstraint(2, constraint.Angle, 3, 4, big.NewFloat((70.0/180.0)*math.Pi), false)
 	c3 := constraint.NewConstraint(3, constraint.Distance, 5, 4, big.NewFloat(1), false)
+	c4 := constraint.NewConstraint(4, constraint.Distance, 5, 2, big.NewFloat(0), false)
+	c5 := constraint.NewConstraint(5, constraint.Distance, 5, 2, big.NewFloat(0), false)
+	c6 := constraint.NewConstraint(6, constraint.Distance, 4, 2, big.NewFloat(0), false)
 	ca.AddConstraint(c0)
 	ca.AddConstraint(c1)
 	ca.AddConstraint(c2)
 	ca.AddConstraint(c3)
+	ca.AddConstraint(c4)
+	ca.AddConstraint(c5)
+	ca.AddConstraint(c6)
 	tests := []struct {
 		name    string
 		c1      *constraint.Constraint
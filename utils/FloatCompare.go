@@ -51,3 +51,20 @@ func FloatCompare(a float64, b float64, tol float64) int {
 func StandardFloatCompare(a float64, b float64) int {
 	return FloatCompare(a, b, StandardCompare)
 }
+
+// AngleDifference returns the signed difference a - b in radians wrapped to the range (-pi, pi]
+func AngleDifference(a float64, b float64) float64 {
+	diff := math.Mod(a-b, 2*math.Pi)
+	if diff > math.Pi {
+		diff -= 2 * math.Pi
+	} else if diff <= -math.Pi {
+		diff += 2 * math.Pi
+	}
+	return diff
+}
+
+// StandardAngleCompare returns 0 if two angles in radians are equal using a tolerance, treating
+// angles a full rotation apart (such as -pi and pi) as equal. Otherwise -1 if a < b, 1 if a > b.
+func StandardAngleCompare(a float64, b float64) int {
+	return StandardFloatCompare(AngleDifference(a, b), 0)
+}
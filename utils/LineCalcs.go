@@ -2,6 +2,8 @@ package utils
 
 import "math/big"
 
+// LineFromPoints returns a, b, and c for the line ax + by + c = 0 through [x1, y1] and [x2, y2].
+// The normal (a, b) is chosen so the direction of the line (-b, a) runs from [x1, y1] to [x2, y2].
 func LineFromPoints(x1, y1, x2, y2 float64) (a, b, c float64) {
 	a = y2 - y1 // y' - y
 	b = x1 - x2 // x - x'